@@ -1,5 +1,8 @@
 module github.com/shapestone/shape-core
 
-go 1.25
+go 1.25.0
 
-require github.com/google/uuid v1.6.0
+require (
+	github.com/google/uuid v1.6.0
+	golang.org/x/text v0.40.0
+)
@@ -0,0 +1,49 @@
+package ast
+
+// Comment is a source comment attached to a node.
+type Comment struct {
+	Text     string   `json:"text"`          // Raw comment text, including delimiters such as "//" or "/* */"
+	Doc      bool     `json:"doc,omitempty"` // True for doc comments ("///", "/** */")
+	Position Position `json:"position"`
+}
+
+// Annotations holds the comments and metadata attached to a node. Leading
+// comments precede the node in the source and trailing comments follow it on
+// the same line. Metadata carries format-specific annotations such as
+// descriptions or deprecation notes; values must be JSON-serializable, and
+// numbers come back as float64 after UnmarshalSchemaNode.
+type Annotations struct {
+	Leading  []Comment              `json:"leading,omitempty"`
+	Trailing []Comment              `json:"trailing,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// IsEmpty returns true if a is nil or holds no comments and no metadata.
+func (a *Annotations) IsEmpty() bool {
+	return a == nil || (len(a.Leading) == 0 && len(a.Trailing) == 0 && len(a.Metadata) == 0)
+}
+
+// Get returns the metadata value for key. It is safe to call on nil.
+func (a *Annotations) Get(key string) (interface{}, bool) {
+	if a == nil {
+		return nil, false
+	}
+	value, ok := a.Metadata[key]
+	return value, ok
+}
+
+// Set sets the metadata value for key.
+func (a *Annotations) Set(key string, value interface{}) {
+	if a.Metadata == nil {
+		a.Metadata = make(map[string]interface{})
+	}
+	a.Metadata[key] = value
+}
+
+// annotationsOrNil returns a, or nil if it holds nothing to serialize.
+func annotationsOrNil(a *Annotations) *Annotations {
+	if a.IsEmpty() {
+		return nil
+	}
+	return a
+}
@@ -6,6 +6,8 @@ import "fmt"
 type ArrayNode struct {
 	elementSchema SchemaNode // Schema for all array elements
 	position      Position
+	end           Position     // End of the source range; see Span
+	annotations   *Annotations // Comments and metadata; nil when there are none
 }
 
 // NewArrayNode creates a new array node.
@@ -31,6 +33,28 @@ func (n *ArrayNode) Position() Position {
 	return n.position
 }
 
+// Span returns the source range of the node.
+func (n *ArrayNode) Span() Span {
+	return Span{Start: n.position, End: n.end}
+}
+
+// SetSpan sets the source range of the node, including its position.
+// Parsers call it once the end of the node is known, before sharing the node.
+func (n *ArrayNode) SetSpan(span Span) {
+	n.position = span.Start
+	n.end = span.End
+}
+
+// Annotations returns the comments and metadata attached to the node, or nil.
+func (n *ArrayNode) Annotations() *Annotations {
+	return n.annotations
+}
+
+// SetAnnotations attaches comments and metadata to the node.
+func (n *ArrayNode) SetAnnotations(annotations *Annotations) {
+	n.annotations = annotations
+}
+
 // Accept implements the visitor pattern.
 func (n *ArrayNode) Accept(visitor Visitor) error {
 	return visitor.VisitArray(n)
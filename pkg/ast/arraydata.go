@@ -9,8 +9,10 @@ import (
 // ArrayDataNode represents actual array data with elements.
 // This is distinct from ArrayNode which represents array schema validation.
 type ArrayDataNode struct {
-	elements []SchemaNode // Actual array elements
-	position Position
+	elements    []SchemaNode // Actual array elements
+	position    Position
+	end         Position     // End of the source range; see Span
+	annotations *Annotations // Comments and metadata; nil when there are none
 }
 
 // arrayDataNodePool reduces allocation overhead by reusing ArrayDataNode objects.
@@ -28,6 +30,8 @@ func NewArrayDataNode(elements []SchemaNode, pos Position) *ArrayDataNode {
 	n := arrayDataNodePool.Get().(*ArrayDataNode)
 	n.elements = elements
 	n.position = pos
+	n.end = ZeroPosition()
+	n.annotations = nil
 	return n
 }
 
@@ -41,6 +45,7 @@ func ReleaseArrayDataNode(n *ArrayDataNode) {
 	// Clear elements slice to prevent memory leaks
 	// Note: We don't release the slice itself to the pool because sizes vary
 	n.elements = nil
+	n.annotations = nil
 	arrayDataNodePool.Put(n)
 }
 
@@ -73,6 +78,28 @@ func (n *ArrayDataNode) Position() Position {
 	return n.position
 }
 
+// Span returns the source range of the node.
+func (n *ArrayDataNode) Span() Span {
+	return Span{Start: n.position, End: n.end}
+}
+
+// SetSpan sets the source range of the node, including its position.
+// Parsers call it once the end of the node is known, before sharing the node.
+func (n *ArrayDataNode) SetSpan(span Span) {
+	n.position = span.Start
+	n.end = span.End
+}
+
+// Annotations returns the comments and metadata attached to the node, or nil.
+func (n *ArrayDataNode) Annotations() *Annotations {
+	return n.annotations
+}
+
+// SetAnnotations attaches comments and metadata to the node.
+func (n *ArrayDataNode) SetAnnotations(annotations *Annotations) {
+	n.annotations = annotations
+}
+
 // Accept implements the visitor pattern.
 func (n *ArrayDataNode) Accept(visitor Visitor) error {
 	return visitor.VisitArrayData(n)
@@ -2,8 +2,12 @@ package ast
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
 	"strings"
 	"testing"
+	"time"
 )
 
 //
@@ -537,6 +541,11 @@ func TestPrettyPrint(t *testing.T) {
 			node:     NewArrayNode(NewTypeNode("String", pos), pos),
 			contains: []string{"Array:", "element:"},
 		},
+		{
+			name:     "ArrayData",
+			node:     NewArrayDataNode([]SchemaNode{NewLiteralNode("a", pos), NewLiteralNode(int64(2), pos)}, pos),
+			contains: []string{"ArrayData:", "[0]:", `Literal: "a"`, "[1]:", "Literal: 2"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -941,3 +950,930 @@ func TestObjectNode_Comprehensive(t *testing.T) {
 	}
 	ReleaseObjectNode(newNode)
 }
+
+//
+// Ordered Object Tests
+//
+
+func orderedTestObject() *ObjectNode {
+	return NewOrderedObjectNode([]PropertyEntry{
+		{Key: "zeta", Value: NewLiteralNode(int64(1), NewPosition(10, 2, 12)), KeyPosition: NewPosition(2, 2, 4)},
+		{Key: "alpha", Value: NewLiteralNode("a", NewPosition(25, 3, 13)), KeyPosition: NewPosition(16, 3, 4)},
+		{Key: "mid", Value: NewTypeNode("UUID", NewPosition(38, 4, 11)), KeyPosition: NewPosition(31, 4, 4)},
+	}, NewPosition(0, 1, 1))
+}
+
+func TestObjectNode_Ordered(t *testing.T) {
+	node := orderedTestObject()
+
+	if got := strings.Join(node.Keys(), ","); got != "zeta,alpha,mid" {
+		t.Errorf("Keys() = %q, want source order", got)
+	}
+	if node.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", node.Len())
+	}
+	if got := node.String(); got != `{"zeta": 1, "alpha": "a", "mid": UUID}` {
+		t.Errorf("String() = %q, want properties in source order", got)
+	}
+
+	prop, ok := node.GetProperty("alpha")
+	if !ok || prop.(*LiteralNode).Value() != "a" {
+		t.Errorf("GetProperty(alpha) = %v, %v", prop, ok)
+	}
+
+	pos, ok := node.KeyPosition("mid")
+	if !ok || pos != NewPosition(31, 4, 4) {
+		t.Errorf("KeyPosition(mid) = %v, %v, want line 4, column 4", pos, ok)
+	}
+	if _, ok := node.KeyPosition("missing"); ok {
+		t.Error("KeyPosition(missing) should not be found")
+	}
+
+	entries := node.PropertyEntries()
+	if len(entries) != 3 || entries[1].Key != "alpha" || entries[1].KeyPosition.Line != 3 {
+		t.Errorf("PropertyEntries() = %v", entries)
+	}
+}
+
+func TestObjectNode_OrderedDuplicateKeys(t *testing.T) {
+	pos := NewPosition(0, 1, 1)
+	node := NewOrderedObjectNode([]PropertyEntry{
+		{Key: "a", Value: NewLiteralNode("first", pos)},
+		{Key: "b", Value: NewLiteralNode("b", pos)},
+		{Key: "a", Value: NewLiteralNode("last", pos)},
+	}, pos)
+
+	if got := strings.Join(node.Keys(), ","); got != "a,b" {
+		t.Errorf("Keys() = %q, want a,b", got)
+	}
+	if prop, _ := node.GetProperty("a"); prop.(*LiteralNode).Value() != "last" {
+		t.Errorf("Expected last value to win, got %v", prop)
+	}
+	if entries := node.PropertyEntries(); entries[0].Value.(*LiteralNode).Value() != "last" {
+		t.Errorf("Expected entry to hold the last value, got %v", entries[0].Value)
+	}
+}
+
+func TestObjectNode_MapOrderIsAlphabetical(t *testing.T) {
+	pos := NewPosition(0, 1, 1)
+	node := NewObjectNode(map[string]SchemaNode{
+		"b": NewLiteralNode(int64(2), pos),
+		"a": NewLiteralNode(int64(1), pos),
+	}, pos)
+
+	if got := strings.Join(node.Keys(), ","); got != "a,b" {
+		t.Errorf("Keys() = %q, want alphabetical order", got)
+	}
+	keyPos, ok := node.KeyPosition("a")
+	if !ok || keyPos.IsValid() {
+		t.Errorf("KeyPosition(a) = %v, %v, want zero position", keyPos, ok)
+	}
+}
+
+func TestObjectNode_OrderedPooling(t *testing.T) {
+	ReleaseObjectNode(orderedTestObject())
+
+	// A node from the pool must not keep the previous order
+	pos := NewPosition(0, 1, 1)
+	node := NewObjectNode(map[string]SchemaNode{"only": NewLiteralNode(true, pos)}, pos)
+	if got := strings.Join(node.Keys(), ","); got != "only" {
+		t.Errorf("Keys() = %q, want only", got)
+	}
+	if _, ok := node.KeyPosition("zeta"); ok {
+		t.Error("Pooled node kept a stale key index")
+	}
+}
+
+func TestObjectNode_OrderedPrinting(t *testing.T) {
+	node := orderedTestObject()
+
+	pretty := PrettyPrint(node)
+	if strings.Index(pretty, `"zeta"`) > strings.Index(pretty, `"alpha"`) {
+		t.Errorf("PrettyPrint() did not keep source order:\n%s", pretty)
+	}
+	tree := TreePrint(node)
+	if strings.Index(tree, `"zeta"`) > strings.Index(tree, `"alpha"`) {
+		t.Errorf("TreePrint() did not keep source order:\n%s", tree)
+	}
+}
+
+func TestObjectNode_OrderedSerialization(t *testing.T) {
+	node := orderedTestObject()
+
+	data, err := json.Marshal(node)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	zeta, alpha, mid := strings.Index(string(data), `"zeta"`), strings.Index(string(data), `"alpha"`), strings.Index(string(data), `"mid"`)
+	if zeta < 0 || zeta > alpha || alpha > mid {
+		t.Errorf("Serialized properties out of order: %s", data)
+	}
+
+	restored, err := UnmarshalSchemaNode(data)
+	if err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	obj, ok := restored.(*ObjectNode)
+	if !ok {
+		t.Fatalf("Expected *ObjectNode, got %T", restored)
+	}
+	if got := strings.Join(obj.Keys(), ","); got != "zeta,alpha,mid" {
+		t.Errorf("Keys() after round trip = %q, want zeta,alpha,mid", got)
+	}
+}
+
+func TestPropertyList_UnmarshalErrors(t *testing.T) {
+	var list PropertyList
+	if err := json.Unmarshal([]byte(`["not", "an", "object"]`), &list); err == nil {
+		t.Error("Expected error for non-object properties")
+	}
+	if err := json.Unmarshal([]byte(`{"a": 1, "b": [2], "a": 3}`), &list); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(list) != 3 || list[2].Key != "a" || string(list[1].Value.(json.RawMessage)) != "[2]" {
+		t.Errorf("Expected every property in input order, got %v", list)
+	}
+}
+
+func duplicateTestEntries() []PropertyEntry {
+	return []PropertyEntry{
+		{Key: "a", Value: NewLiteralNode("first", NewPosition(5, 1, 6)), KeyPosition: NewPosition(1, 1, 2)},
+		{Key: "b", Value: NewLiteralNode("b", NewPosition(17, 2, 6)), KeyPosition: NewPosition(13, 2, 2)},
+		{Key: "a", Value: NewLiteralNode("second", NewPosition(26, 3, 6)), KeyPosition: NewPosition(22, 3, 2)},
+		{Key: "a", Value: NewLiteralNode("third", NewPosition(40, 4, 6)), KeyPosition: NewPosition(36, 4, 2)},
+	}
+}
+
+func TestObjectNodeWithPolicy(t *testing.T) {
+	tests := []struct {
+		policy   DuplicateKeyPolicy
+		name     string
+		keys     string
+		value    string
+		all      int
+		entries  int
+		keyLine  int
+		printed  string
+		policyID string
+	}{
+		{DuplicateKeyLastWins, "last wins", "a,b", "third", 1, 2, 4, `{"a": "third", "b": "b"}`, "last-wins"},
+		{DuplicateKeyFirstWins, "first wins", "a,b", "first", 1, 2, 1, `{"a": "first", "b": "b"}`, "first-wins"},
+		{DuplicateKeyKeepAll, "keep all", "a,b", "third", 3, 4, 4, `{"a": "first", "b": "b", "a": "second", "a": "third"}`, "keep-all"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := NewObjectNodeWithPolicy(duplicateTestEntries(), NewPosition(0, 1, 1), tt.policy)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := strings.Join(node.Keys(), ","); got != tt.keys {
+				t.Errorf("Keys() = %q, want %q", got, tt.keys)
+			}
+			if prop, _ := node.GetProperty("a"); prop.(*LiteralNode).Value() != tt.value {
+				t.Errorf("GetProperty(a) = %v, want %q", prop, tt.value)
+			}
+			if got := len(node.GetAll("a")); got != tt.all {
+				t.Errorf("len(GetAll(a)) = %d, want %d", got, tt.all)
+			}
+			if got := len(node.PropertyEntries()); got != tt.entries {
+				t.Errorf("len(PropertyEntries()) = %d, want %d", got, tt.entries)
+			}
+			if pos, _ := node.KeyPosition("a"); pos.Line != tt.keyLine {
+				t.Errorf("KeyPosition(a) line = %d, want %d", pos.Line, tt.keyLine)
+			}
+			if node.String() != tt.printed {
+				t.Errorf("String() = %s, want %s", node.String(), tt.printed)
+			}
+			if tt.policy.String() != tt.policyID {
+				t.Errorf("String() = %q, want %q", tt.policy.String(), tt.policyID)
+			}
+
+			dups := node.Duplicates()
+			if len(dups) != 1 || dups[0].Key != "a" || len(dups[0].Positions) != 3 {
+				t.Fatalf("Duplicates() = %v, want key a with three positions", dups)
+			}
+			for i, line := range []int{1, 3, 4} {
+				if dups[0].Positions[i].Line != line {
+					t.Errorf("Duplicates()[0].Positions[%d].Line = %d, want %d", i, dups[0].Positions[i].Line, line)
+				}
+			}
+		})
+	}
+}
+
+func TestObjectNodeWithPolicy_Reject(t *testing.T) {
+	node, err := NewObjectNodeWithPolicy(duplicateTestEntries(), NewPosition(0, 1, 1), DuplicateKeyReject)
+	if node != nil {
+		t.Errorf("Expected no node, got %v", node)
+	}
+
+	var dup *DuplicateKeyError
+	if !errors.As(err, &dup) {
+		t.Fatalf("Expected *DuplicateKeyError, got %v", err)
+	}
+	if dup.Key != "a" || dup.First.Line != 1 || dup.Duplicate.Line != 3 {
+		t.Errorf("DuplicateKeyError = %+v, want key a first at line 1, repeated at line 3", dup)
+	}
+	if want := `duplicate key "a" at line 3, column 2 (first defined at line 1, column 2)`; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	if _, err := NewObjectNodeWithPolicy(duplicateTestEntries()[:2], NewPosition(0, 1, 1), DuplicateKeyReject); err != nil {
+		t.Errorf("Unexpected error without duplicates: %v", err)
+	}
+}
+
+func TestObjectNodeWithPolicy_KeepAllSerialization(t *testing.T) {
+	node, _ := NewObjectNodeWithPolicy(duplicateTestEntries(), NewPosition(0, 1, 1), DuplicateKeyKeepAll)
+	data, err := json.Marshal(node)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+
+	restored, err := UnmarshalSchemaNode(data)
+	if err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	obj := restored.(*ObjectNode)
+	if got := len(obj.GetAll("a")); got != 3 {
+		t.Errorf("len(GetAll(a)) after round trip = %d, want 3", got)
+	}
+	if obj.String() != node.String() {
+		t.Errorf("String() after round trip = %s, want %s", obj.String(), node.String())
+	}
+}
+
+func TestObjectNode_PropertyEntryPositions(t *testing.T) {
+	// {"name": "x"} with the key at column 2 and ':' at column 8
+	node := NewOrderedObjectNode([]PropertyEntry{{
+		Key:               "name",
+		Value:             NewLiteralNode("x", NewPosition(9, 1, 10)),
+		KeyPosition:       NewPosition(1, 1, 2),
+		SeparatorPosition: NewPosition(7, 1, 8),
+	}}, NewPosition(0, 1, 1))
+
+	entry := node.PropertyEntries()[0]
+	if entry.KeyPosition.Column != 2 || entry.SeparatorPosition.Column != 8 {
+		t.Errorf("PropertyEntries()[0] positions = %v, %v, want columns 2 and 8", entry.KeyPosition, entry.SeparatorPosition)
+	}
+
+	data, err := json.Marshal(node)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	restored, err := UnmarshalSchemaNode(data)
+	if err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if got := restored.(*ObjectNode).PropertyEntries()[0]; got.KeyPosition != entry.KeyPosition || got.SeparatorPosition != entry.SeparatorPosition {
+		t.Errorf("Positions after round trip = %v, %v, want %v, %v", got.KeyPosition, got.SeparatorPosition, entry.KeyPosition, entry.SeparatorPosition)
+	}
+
+	// Objects without key positions do not serialize them
+	plain, _ := json.Marshal(NewObjectNode(map[string]SchemaNode{"a": NewLiteralNode(true, ZeroPosition())}, ZeroPosition()))
+	if strings.Contains(string(plain), "propertyPositions") {
+		t.Errorf("Expected no property positions, got %s", plain)
+	}
+}
+
+//
+// Span Tests
+//
+
+func TestSpan(t *testing.T) {
+	source := "{\n  \"a\": [1, 2]\n}"
+	array := NewSpan(NewPosition(9, 2, 8), NewPosition(15, 2, 14))
+
+	if !array.IsValid() {
+		t.Error("Expected span to be valid")
+	}
+	if array.Len() != 6 {
+		t.Errorf("Len() = %d, want 6", array.Len())
+	}
+	if got := array.Text(source); got != "[1, 2]" {
+		t.Errorf("Text() = %q, want %q", got, "[1, 2]")
+	}
+	if !array.Contains(NewPosition(9, 2, 8)) || array.Contains(NewPosition(15, 2, 14)) {
+		t.Error("Expected span to include its start and exclude its end")
+	}
+	if array.String() != "line 2, columns 8-14" {
+		t.Errorf("String() = %q", array.String())
+	}
+
+	object := NewSpan(NewPosition(0, 1, 1), NewPosition(len(source), 3, 2))
+	if object.Text(source) != source {
+		t.Errorf("Text() = %q, want whole source", object.Text(source))
+	}
+	if object.String() != "line 1, column 1 to line 3, column 2" {
+		t.Errorf("String() = %q", object.String())
+	}
+
+	open := NewSpan(NewPosition(0, 1, 1), ZeroPosition())
+	if open.IsValid() || open.Len() != 0 || open.Text(source) != "" || open.Contains(NewPosition(0, 1, 1)) {
+		t.Error("Expected span without an end to be empty")
+	}
+	if open.String() != "line 1, column 1" {
+		t.Errorf("String() = %q", open.String())
+	}
+
+	outside := NewSpan(NewPosition(10, 1, 11), NewPosition(40, 1, 41))
+	if outside.Text(source) != "" {
+		t.Errorf("Text() past the end of source = %q, want empty", outside.Text(source))
+	}
+
+	// Offsets count runes, as the tokenizer reports them
+	text := `{"né": "日本"}`
+	value := NewSpan(NewPosition(7, 1, 8), NewPosition(11, 1, 12))
+	if got := value.Text(text); got != `"日本"` {
+		t.Errorf("Text() = %q, want %q", got, `"日本"`)
+	}
+	if value.Len() != 4 {
+		t.Errorf("Len() = %d, want 4", value.Len())
+	}
+	if got := NewSpan(NewPosition(12, 1, 13), NewPosition(13, 1, 14)).Text(text); got != "" {
+		t.Errorf("Text() past the last rune = %q, want empty", got)
+	}
+}
+
+func TestSetSpan(t *testing.T) {
+	start := NewPosition(4, 1, 5)
+	span := NewSpan(start, NewPosition(10, 1, 11))
+	elem := NewLiteralNode(int64(1), ZeroPosition())
+
+	nodes := []interface {
+		SchemaNode
+		Spanned
+		SetSpan(Span)
+	}{
+		NewLiteralNode("x", ZeroPosition()),
+		NewTypeNode("UUID", ZeroPosition()),
+		NewFunctionNode("Integer", []interface{}{int64(1)}, ZeroPosition()),
+		NewObjectNode(map[string]SchemaNode{}, ZeroPosition()),
+		NewArrayNode(elem, ZeroPosition()),
+		NewArrayDataNode([]SchemaNode{elem}, ZeroPosition()),
+	}
+
+	for _, node := range nodes {
+		t.Run(node.Type().String(), func(t *testing.T) {
+			if node.Span().IsValid() {
+				t.Errorf("Expected no span before SetSpan, got %v", node.Span())
+			}
+
+			node.SetSpan(span)
+			if node.Span() != span {
+				t.Errorf("Span() = %v, want %v", node.Span(), span)
+			}
+			if node.Position() != start {
+				t.Errorf("Position() = %v, want %v", node.Position(), start)
+			}
+
+			data, err := json.Marshal(node)
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			restored, err := UnmarshalSchemaNode(data)
+			if err != nil {
+				t.Fatalf("Unmarshal error: %v", err)
+			}
+			if got := restored.(Spanned).Span(); got != span {
+				t.Errorf("Span() after round trip = %v, want %v", got, span)
+			}
+		})
+	}
+}
+
+func TestSpan_PooledNodesReset(t *testing.T) {
+	span := NewSpan(NewPosition(0, 1, 1), NewPosition(3, 1, 4))
+
+	lit := NewLiteralNode(int64(1), ZeroPosition())
+	lit.SetSpan(span)
+	ReleaseLiteralNode(lit)
+	if NewLiteralNode(int64(2), ZeroPosition()).Span().End.IsValid() {
+		t.Error("Expected reused LiteralNode to have no end")
+	}
+
+	obj := NewOrderedObjectNode(nil, ZeroPosition())
+	obj.SetSpan(span)
+	ReleaseObjectNode(obj)
+	if NewObjectNode(nil, ZeroPosition()).Span().End.IsValid() {
+		t.Error("Expected reused ObjectNode to have no end")
+	}
+
+	arr := NewArrayDataNode(nil, ZeroPosition())
+	arr.SetSpan(span)
+	ReleaseArrayDataNode(arr)
+	if NewArrayDataNode(nil, ZeroPosition()).Span().End.IsValid() {
+		t.Error("Expected reused ArrayDataNode to have no end")
+	}
+}
+
+func TestSpan_OmittedWhenUnknown(t *testing.T) {
+	data, err := json.Marshal(NewLiteralNode("x", NewPosition(0, 1, 1)))
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if strings.Contains(string(data), `"end"`) {
+		t.Errorf("Expected no end, got %s", data)
+	}
+}
+
+//
+// Annotations Tests
+//
+
+func TestAnnotations(t *testing.T) {
+	var none *Annotations
+	if !none.IsEmpty() {
+		t.Error("Expected nil annotations to be empty")
+	}
+	if _, ok := none.Get("description"); ok {
+		t.Error("Expected Get on nil annotations to report no value")
+	}
+
+	a := &Annotations{}
+	if !a.IsEmpty() {
+		t.Error("Expected new annotations to be empty")
+	}
+	a.Set("deprecated", true)
+	if value, ok := a.Get("deprecated"); !ok || value != true {
+		t.Errorf("Get(deprecated) = %v, %v, want true, true", value, ok)
+	}
+	if a.IsEmpty() {
+		t.Error("Expected annotations with metadata not to be empty")
+	}
+
+	elem := NewLiteralNode(int64(1), ZeroPosition())
+	nodes := []SchemaNode{
+		elem,
+		NewTypeNode("UUID", ZeroPosition()),
+		NewFunctionNode("Integer", nil, ZeroPosition()),
+		NewObjectNode(nil, ZeroPosition()),
+		NewArrayNode(elem, ZeroPosition()),
+		NewArrayDataNode(nil, ZeroPosition()),
+	}
+	for _, node := range nodes {
+		if annotated, ok := node.(Annotated); !ok || annotated.Annotations() != nil {
+			t.Errorf("Expected %s to implement Annotated without annotations", node.Type())
+		}
+	}
+}
+
+func TestAnnotations_Serialization(t *testing.T) {
+	annotations := &Annotations{
+		Leading:  []Comment{{Text: "/// The user's name", Doc: true, Position: NewPosition(2, 1, 3)}},
+		Trailing: []Comment{{Text: "// required", Position: NewPosition(30, 2, 20)}},
+		Metadata: map[string]interface{}{"description": "Display name", "deprecated": false},
+	}
+
+	name := NewLiteralNode("alice", NewPosition(24, 2, 14))
+	name.SetAnnotations(annotations)
+	obj := NewOrderedObjectNode([]PropertyEntry{{Key: "name", Value: name}}, NewPosition(0, 1, 1))
+	obj.SetAnnotations(&Annotations{Metadata: map[string]interface{}{"title": "User"}})
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	restored, err := UnmarshalSchemaNode(data)
+	if err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+
+	if title, _ := restored.(Annotated).Annotations().Get("title"); title != "User" {
+		t.Errorf("Object title = %v, want User", title)
+	}
+
+	value, _ := restored.(*ObjectNode).GetProperty("name")
+	got := value.(Annotated).Annotations()
+	if got == nil {
+		t.Fatal("Expected property value to keep its annotations")
+	}
+	if len(got.Leading) != 1 || got.Leading[0] != annotations.Leading[0] {
+		t.Errorf("Leading = %v, want %v", got.Leading, annotations.Leading)
+	}
+	if len(got.Trailing) != 1 || got.Trailing[0] != annotations.Trailing[0] {
+		t.Errorf("Trailing = %v, want %v", got.Trailing, annotations.Trailing)
+	}
+	if description, _ := got.Get("description"); description != "Display name" {
+		t.Errorf("description = %v, want Display name", description)
+	}
+
+	// Empty annotations are not serialized
+	plain := NewTypeNode("UUID", ZeroPosition())
+	plain.SetAnnotations(&Annotations{})
+	data, _ = json.Marshal(plain)
+	if strings.Contains(string(data), "annotations") {
+		t.Errorf("Expected no annotations, got %s", data)
+	}
+}
+
+func TestAnnotations_PooledNodesReset(t *testing.T) {
+	annotations := &Annotations{Leading: []Comment{{Text: "// x"}}}
+
+	lit := NewLiteralNode(int64(1), ZeroPosition())
+	lit.SetAnnotations(annotations)
+	ReleaseLiteralNode(lit)
+	if NewLiteralNode(int64(2), ZeroPosition()).Annotations() != nil {
+		t.Error("Expected reused LiteralNode to have no annotations")
+	}
+
+	obj := NewObjectNode(nil, ZeroPosition())
+	obj.SetAnnotations(annotations)
+	ReleaseObjectNode(obj)
+	if NewOrderedObjectNode(nil, ZeroPosition()).Annotations() != nil {
+		t.Error("Expected reused ObjectNode to have no annotations")
+	}
+
+	arr := NewArrayDataNode(nil, ZeroPosition())
+	arr.SetAnnotations(annotations)
+	ReleaseArrayDataNode(arr)
+	if NewArrayDataNode(nil, ZeroPosition()).Annotations() != nil {
+		t.Error("Expected reused ArrayDataNode to have no annotations")
+	}
+}
+
+//
+// Rich Literal Tests
+//
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input    string
+		unscaled string
+		scale    int32
+		str      string
+	}{
+		{"123.4500", "1234500", 4, "123.4500"},
+		{"-0.05", "-5", 2, "-0.05"},
+		{"+7", "7", 0, "7"},
+		{".5", "5", 1, "0.5"},
+		{"5.", "5", 0, "5"},
+		{"6.02e23", "602", -21, "602e21"},
+		{"1.5E-3", "15", 4, "0.0015"},
+		{"99999999999999999999.99", "9999999999999999999999", 2, "99999999999999999999.99"},
+		{"1e-10000", "1", 10000, "0." + strings.Repeat("0", 9999) + "1"},
+		{"1e10000", "1", -10000, "1e10000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			d, err := ParseDecimal(tt.input)
+			if err != nil {
+				t.Fatalf("ParseDecimal(%q) error: %v", tt.input, err)
+			}
+			if d.Unscaled().String() != tt.unscaled || d.Scale() != tt.scale {
+				t.Errorf("ParseDecimal(%q) = %s×10^-%d, want %s×10^-%d", tt.input, d.Unscaled(), d.Scale(), tt.unscaled, tt.scale)
+			}
+			if d.String() != tt.str {
+				t.Errorf("String() = %q, want %q", d.String(), tt.str)
+			}
+		})
+	}
+
+	invalid := []string{
+		"", "-", ".", "1.2.3", "1e", "1e+", "0x10", "1_000", "1e99999999999",
+		"1e-2000000000", "1e2000000000", "1e-10001", "0.1e-10000", "0." + strings.Repeat("0", 10001),
+	}
+	for _, input := range invalid {
+		if _, err := ParseDecimal(input); err == nil {
+			t.Errorf("ParseDecimal(%.20q) expected error", input)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewDecimal with scale beyond MaxDecimalScale should panic")
+		}
+	}()
+	NewDecimal(big.NewInt(1), MaxDecimalScale+1)
+}
+
+func TestDecimal_Cmp(t *testing.T) {
+	parse := func(s string) Decimal {
+		d, err := ParseDecimal(s)
+		if err != nil {
+			t.Fatalf("ParseDecimal(%q) error: %v", s, err)
+		}
+		return d
+	}
+
+	if parse("1.50").Cmp(parse("1.5")) != 0 {
+		t.Error("Expected 1.50 to equal 1.5")
+	}
+	if parse("0.1").Cmp(parse("0.09")) != 1 || parse("-2").Cmp(parse("1e-9")) != -1 {
+		t.Error("Unexpected ordering")
+	}
+	if parse("1e3").Cmp(parse("1000.0")) != 0 {
+		t.Error("Expected 1e3 to equal 1000.0")
+	}
+
+	var zero Decimal
+	if zero.String() != "0" || zero.Sign() != 0 || zero.Cmp(parse("0.00")) != 0 {
+		t.Errorf("Unexpected zero Decimal: %s", zero)
+	}
+	if parse("2.5").Rat().String() != "5/2" {
+		t.Errorf("Rat() = %s, want 5/2", parse("2.5").Rat())
+	}
+
+	// NewDecimal copies its argument
+	unscaled := big.NewInt(125)
+	d := NewDecimal(unscaled, 2)
+	unscaled.SetInt64(0)
+	if d.String() != "1.25" {
+		t.Errorf("NewDecimal String() = %q, want 1.25", d.String())
+	}
+}
+
+func TestLiteralNode_RichValues(t *testing.T) {
+	id, _ := new(big.Int).SetString("123456789012345678901", 10)
+	price, _ := ParseDecimal("19.990")
+	stamp := time.Date(2024, 3, 1, 12, 30, 0, 500, time.FixedZone("", -5*3600))
+	bigFloat, _, _ := big.ParseFloat("0x1.8p-2000", 0, 64, big.ToNearestEven)
+
+	tests := []struct {
+		name  string
+		value interface{}
+		str   string
+		equal func(interface{}) bool
+	}{
+		{"big int", id, "123456789012345678901", func(v interface{}) bool {
+			n, ok := v.(*big.Int)
+			return ok && n.Cmp(id) == 0
+		}},
+		{"decimal", price, "19.990", func(v interface{}) bool {
+			d, ok := v.(Decimal)
+			return ok && d.String() == "19.990"
+		}},
+		{"bytes", []byte("hi\x00"), "0x686900", func(v interface{}) bool {
+			b, ok := v.([]byte)
+			return ok && string(b) == "hi\x00"
+		}},
+		{"time", stamp, "2024-03-01T12:30:00.0000005-05:00", func(v interface{}) bool {
+			ts, ok := v.(time.Time)
+			return ok && ts.Equal(stamp)
+		}},
+		{"big float", bigFloat, "1.3064714724325825013e-602", func(v interface{}) bool {
+			f, ok := v.(*big.Float)
+			return ok && f.Cmp(bigFloat) == 0
+		}},
+		{"int64 past 2^53", int64(1<<53 + 1), "9007199254740993", func(v interface{}) bool {
+			n, ok := v.(int64)
+			return ok && n == 1<<53+1
+		}},
+		{"negative int64 past 2^53", int64(-(1<<53 + 1)), "-9007199254740993", func(v interface{}) bool {
+			n, ok := v.(int64)
+			return ok && n == -(1<<53+1)
+		}},
+		{"max uint64", uint64(math.MaxUint64), "18446744073709551615", func(v interface{}) bool {
+			n, ok := v.(uint64)
+			return ok && n == math.MaxUint64
+		}},
+		{"int64 at 2^53", int64(1 << 53), "9007199254740992", func(v interface{}) bool {
+			// Exactly representable, so it stays a plain JSON number
+			f, ok := v.(float64)
+			return ok && f == 1<<53
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := NewLiteralNode(tt.value, NewPosition(0, 1, 1))
+			if node.String() != tt.str {
+				t.Errorf("String() = %q, want %q", node.String(), tt.str)
+			}
+
+			data, err := json.Marshal(node)
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			restored, err := UnmarshalSchemaNode(data)
+			if err != nil {
+				t.Fatalf("Unmarshal error: %v", err)
+			}
+			if value := restored.(*LiteralNode).Value(); !tt.equal(value) {
+				t.Errorf("Value() after round trip = %#v (%T), from %s", value, value, data)
+			}
+		})
+	}
+}
+
+func TestLiteralNode_Raw(t *testing.T) {
+	node := NewLiteralNode(int64(31), NewPosition(0, 1, 1))
+	if node.Raw() != "" {
+		t.Errorf("Raw() = %q, want empty", node.Raw())
+	}
+	node.SetRaw("0x1F")
+
+	data, err := json.Marshal(node)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	restored, err := UnmarshalSchemaNode(data)
+	if err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if raw := restored.(*LiteralNode).Raw(); raw != "0x1F" {
+		t.Errorf("Raw() after round trip = %q, want 0x1F", raw)
+	}
+
+	ReleaseLiteralNode(node)
+	if NewLiteralNode("x", ZeroPosition()).Raw() != "" {
+		t.Error("Expected reused LiteralNode to have no raw lexeme")
+	}
+}
+
+func TestLiteralNode_RichValueErrors(t *testing.T) {
+	inputs := []string{
+		`{"type":"literal","value":"12x","valueType":"bigint"}`,
+		`{"type":"literal","value":"1..2","valueType":"decimal"}`,
+		`{"type":"literal","value":"***","valueType":"bytes"}`,
+		`{"type":"literal","value":"yesterday","valueType":"time"}`,
+		`{"type":"literal","value":12,"valueType":"bigint"}`,
+		`{"type":"literal","value":"18446744073709551616","valueType":"uint64"}`,
+		`{"type":"literal","value":"1.5","valueType":"int64"}`,
+		`{"type":"literal","value":"0x.8q+1","valueType":"bigfloat"}`,
+		`{"type":"literal","value":"1","valueType":"complex"}`,
+	}
+	for _, input := range inputs {
+		if _, err := UnmarshalSchemaNode([]byte(input)); err == nil {
+			t.Errorf("UnmarshalSchemaNode(%s) expected error", input)
+		}
+	}
+}
+
+//
+// Literal Accessor Tests
+//
+
+func TestLiteralNode_Kind(t *testing.T) {
+	dec, _ := ParseDecimal("1.5")
+	tests := []struct {
+		value interface{}
+		kind  LiteralKind
+		name  string
+	}{
+		{nil, LiteralNull, "Null"},
+		{"s", LiteralString, "String"},
+		{true, LiteralBool, "Bool"},
+		{int64(1), LiteralInt, "Int"},
+		{uint8(1), LiteralInt, "Int"},
+		{1.5, LiteralFloat, "Float"},
+		{big.NewInt(1), LiteralBigInt, "BigInt"},
+		{big.NewFloat(1), LiteralBigFloat, "BigFloat"},
+		{dec, LiteralDecimal, "Decimal"},
+		{[]byte{1}, LiteralBytes, "Bytes"},
+		{time.Time{}, LiteralTime, "Time"},
+		{struct{}{}, LiteralOther, "Other"},
+	}
+
+	for _, tt := range tests {
+		node := NewLiteralNode(tt.value, ZeroPosition())
+		if node.Kind() != tt.kind {
+			t.Errorf("Kind() of %#v = %v, want %v", tt.value, node.Kind(), tt.kind)
+		}
+		if node.Kind().String() != tt.name {
+			t.Errorf("Kind().String() = %q, want %q", node.Kind().String(), tt.name)
+		}
+		if node.IsNull() != (tt.kind == LiteralNull) {
+			t.Errorf("IsNull() of %#v = %v", tt.value, node.IsNull())
+		}
+	}
+
+	if LiteralKind(99).String() != "Unknown" {
+		t.Errorf("Unexpected string for invalid kind: %s", LiteralKind(99))
+	}
+	if !LiteralDecimal.IsNumeric() || !LiteralBigFloat.IsNumeric() || LiteralString.IsNumeric() {
+		t.Error("Unexpected IsNumeric result")
+	}
+}
+
+func TestLiteralNode_TypedAccessors(t *testing.T) {
+	if s, ok := NewLiteralNode("x", ZeroPosition()).AsString(); !ok || s != "x" {
+		t.Errorf("AsString() = %q, %v", s, ok)
+	}
+	if _, ok := NewLiteralNode(int64(1), ZeroPosition()).AsString(); ok {
+		t.Error("Expected AsString to reject a number")
+	}
+	if b, ok := NewLiteralNode(true, ZeroPosition()).AsBool(); !ok || !b {
+		t.Errorf("AsBool() = %v, %v", b, ok)
+	}
+	if _, ok := NewLiteralNode("true", ZeroPosition()).AsBool(); ok {
+		t.Error("Expected AsBool to reject a string")
+	}
+	if b, ok := NewLiteralNode([]byte("ab"), ZeroPosition()).AsBytes(); !ok || string(b) != "ab" {
+		t.Errorf("AsBytes() = %q, %v", b, ok)
+	}
+	stamp := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if ts, ok := NewLiteralNode(stamp, ZeroPosition()).AsTime(); !ok || !ts.Equal(stamp) {
+		t.Errorf("AsTime() = %v, %v", ts, ok)
+	}
+}
+
+func TestLiteralNode_AsInt64(t *testing.T) {
+	huge, _ := new(big.Int).SetString("9223372036854775808", 10)
+	whole, _ := ParseDecimal("42.000")
+	fraction, _ := ParseDecimal("42.5")
+
+	tests := []struct {
+		value interface{}
+		want  int64
+		err   error
+	}{
+		{int64(-7), -7, nil},
+		{int32(7), 7, nil},
+		{uint64(math.MaxInt64), math.MaxInt64, nil},
+		{uint64(math.MaxUint64), 0, ErrLiteralRange},
+		{3.0, 3, nil},
+		{3.5, 0, ErrLiteralFraction},
+		{1e19, 0, ErrLiteralRange},
+		{math.NaN(), 0, ErrLiteralRange},
+		{big.NewInt(-12), -12, nil},
+		{huge, 0, ErrLiteralRange},
+		{whole, 42, nil},
+		{fraction, 0, ErrLiteralFraction},
+		{big.NewFloat(-12), -12, nil},
+		{big.NewFloat(12.5), 0, ErrLiteralFraction},
+		{new(big.Float).SetInf(false), 0, ErrLiteralRange},
+		{"12", 0, ErrLiteralType},
+		{nil, 0, ErrLiteralType},
+	}
+
+	for _, tt := range tests {
+		got, err := NewLiteralNode(tt.value, ZeroPosition()).AsInt64()
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("AsInt64() of %#v = %d, %v, want %d, %v", tt.value, got, err, tt.want, tt.err)
+		}
+	}
+
+	_, err := NewLiteralNode(3.5, ZeroPosition()).AsInt64()
+	if want := "cannot convert Float literal 3.5 to integer: number is not an integer"; err == nil || err.Error() != want {
+		t.Errorf("AsInt64() error = %v, want %q", err, want)
+	}
+}
+
+func TestLiteralNode_AsFloat64(t *testing.T) {
+	huge := new(big.Int).Lsh(big.NewInt(1), 1100)
+	price, _ := ParseDecimal("19.99")
+	tiny, _ := ParseDecimal("1e400")
+	hugeFloat, _, _ := big.ParseFloat("1e400", 10, 128, big.ToNearestEven)
+
+	tests := []struct {
+		value interface{}
+		want  float64
+		err   error
+	}{
+		{2.5, 2.5, nil},
+		{float32(0.5), 0.5, nil},
+		{int64(-3), -3, nil},
+		{uint64(1 << 63), 1 << 63, nil},
+		{big.NewInt(1 << 40), 1 << 40, nil},
+		{huge, 0, ErrLiteralRange},
+		{price, 19.99, nil},
+		{tiny, 0, ErrLiteralRange},
+		{big.NewFloat(0.25), 0.25, nil},
+		{hugeFloat, 0, ErrLiteralRange},
+		{true, 0, ErrLiteralType},
+	}
+
+	for _, tt := range tests {
+		got, err := NewLiteralNode(tt.value, ZeroPosition()).AsFloat64()
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("AsFloat64() of %#v = %g, %v, want %g, %v", tt.value, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestLiteralNode_AsBigIntAndDecimal(t *testing.T) {
+	source := big.NewInt(5)
+	node := NewLiteralNode(source, ZeroPosition())
+	i, err := node.AsBigInt()
+	if err != nil || i.Int64() != 5 {
+		t.Fatalf("AsBigInt() = %v, %v", i, err)
+	}
+	i.SetInt64(6)
+	if source.Int64() != 5 {
+		t.Error("Expected AsBigInt to return a copy")
+	}
+
+	tests := []struct {
+		value interface{}
+		want  string
+		err   error
+	}{
+		{0.1, "0.1", nil},
+		{float32(0.1), "0.1", nil},
+		{int64(-20), "-20", nil},
+		{big.NewInt(7), "7", nil},
+		{big.NewFloat(0.1), "0.1", nil},
+		{new(big.Float).SetInf(true), "", ErrLiteralRange},
+		{math.Inf(1), "", ErrLiteralRange},
+		{"0.1", "", ErrLiteralType},
+	}
+	for _, tt := range tests {
+		d, err := NewLiteralNode(tt.value, ZeroPosition()).AsDecimal()
+		if !errors.Is(err, tt.err) || (err == nil && d.String() != tt.want) {
+			t.Errorf("AsDecimal() of %#v = %s, %v, want %s, %v", tt.value, d, err, tt.want, tt.err)
+		}
+	}
+}
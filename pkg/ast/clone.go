@@ -0,0 +1,334 @@
+package ast
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"math"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// Clone returns a deep copy of node, including positions, spans, raw
+// lexemes and annotations. The copy shares nothing mutable with node, so
+// either can be modified or released without affecting the other. Nodes of
+// types outside this package are returned as they are.
+func Clone(node SchemaNode) SchemaNode {
+	switch n := node.(type) {
+	case nil:
+		return nil
+
+	case *LiteralNode:
+		c := NewLiteralNode(cloneValue(n.value), n.position)
+		c.raw = n.raw
+		c.end = n.end
+		c.annotations = cloneAnnotations(n.annotations)
+		return c
+
+	case *TypeNode:
+		c := NewTypeNode(n.typeName, n.position)
+		c.end = n.end
+		c.annotations = cloneAnnotations(n.annotations)
+		return c
+
+	case *FunctionNode:
+		var args []interface{}
+		if n.arguments != nil {
+			args = make([]interface{}, len(n.arguments))
+			for i, arg := range n.arguments {
+				args[i] = cloneValue(arg)
+			}
+		}
+		c := NewFunctionNode(n.name, args, n.position)
+		c.end = n.end
+		c.annotations = cloneAnnotations(n.annotations)
+		return c
+
+	case *ObjectNode:
+		return cloneObject(n)
+
+	case *ArrayNode:
+		c := NewArrayNode(Clone(n.elementSchema), n.position)
+		c.end = n.end
+		c.annotations = cloneAnnotations(n.annotations)
+		return c
+
+	case *ArrayDataNode:
+		var elements []SchemaNode
+		if n.elements != nil {
+			elements = make([]SchemaNode, len(n.elements))
+			for i, elem := range n.elements {
+				elements[i] = Clone(elem)
+			}
+		}
+		c := NewArrayDataNode(elements, n.position)
+		c.end = n.end
+		c.annotations = cloneAnnotations(n.annotations)
+		return c
+
+	default:
+		return node
+	}
+}
+
+// cloneObject deep-copies an object node, keeping each property's value the
+// same node in both the map and the ordered entries.
+func cloneObject(n *ObjectNode) *ObjectNode {
+	c := NewObjectNode(nil, n.position)
+	c.end = n.end
+	c.annotations = cloneAnnotations(n.annotations)
+	if n.properties != nil {
+		c.properties = make(map[string]SchemaNode, len(n.properties))
+	}
+
+	if n.entries == nil {
+		for key, value := range n.properties {
+			c.properties[key] = Clone(value)
+		}
+		return c
+	}
+
+	c.entries = make([]PropertyEntry, len(n.entries))
+	for i, entry := range n.entries {
+		entry.Value = Clone(entry.Value)
+		c.entries[i] = entry
+	}
+	c.index = make(map[string]int, len(n.index))
+	for key, i := range n.index {
+		c.index[key] = i
+		c.properties[key] = c.entries[i].Value
+	}
+	if n.duplicates != nil {
+		c.duplicates = make([]DuplicateKey, len(n.duplicates))
+		for i, dup := range n.duplicates {
+			c.duplicates[i] = DuplicateKey{Key: dup.Key, Positions: append([]Position(nil), dup.Positions...)}
+		}
+	}
+	return c
+}
+
+// cloneValue copies literal and argument values that are mutable.
+func cloneValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *big.Int:
+		if v == nil {
+			return v
+		}
+		return new(big.Int).Set(v)
+	case *big.Float:
+		if v == nil {
+			return v
+		}
+		return new(big.Float).Copy(v)
+	case []byte:
+		if v == nil {
+			return v
+		}
+		return append([]byte{}, v...)
+	default:
+		return value
+	}
+}
+
+// cloneAnnotations copies the comments and the metadata map. Metadata values
+// themselves are shared.
+func cloneAnnotations(a *Annotations) *Annotations {
+	if a == nil {
+		return nil
+	}
+	c := &Annotations{
+		Leading:  append([]Comment(nil), a.Leading...),
+		Trailing: append([]Comment(nil), a.Trailing...),
+	}
+	if a.Metadata != nil {
+		c.Metadata = make(map[string]interface{}, len(a.Metadata))
+		for key, value := range a.Metadata {
+			c.Metadata[key] = value
+		}
+	}
+	return c
+}
+
+// Hash returns a structural hash of node that is stable across runs and
+// processes. It follows the equality of grammar.ASTEqual: positions, spans,
+// annotations and raw lexemes are ignored, object properties are hashed by
+// key regardless of order, with every occurrence of a key kept by
+// DuplicateKeyKeepAll in source order, decimals by value and times as instants. Equal
+// trees hash alike, but different trees may collide, so confirm matches with
+// grammar.ASTEqual when deduplicating.
+func Hash(node SchemaNode) uint64 {
+	h := &nodeHasher{h: fnv.New64a()}
+	h.node(node)
+	return h.h.Sum64()
+}
+
+// Tags written before each node and value so that different shapes cannot
+// produce the same byte stream.
+const (
+	hashNil byte = iota
+	hashLiteral
+	hashType
+	hashFunction
+	hashObject
+	hashArray
+	hashArrayData
+	hashUnknownNode
+	hashString
+	hashBool
+	hashInt64
+	hashFloat64
+	hashBigInt
+	hashDecimal
+	hashBytes
+	hashTime
+	hashOther
+	hashBigFloat
+)
+
+// nodeHasher writes an unambiguous encoding of a tree into a hash.
+type nodeHasher struct {
+	h   hash.Hash64
+	buf [8]byte
+}
+
+// tag writes a single byte. Writes to a hash.Hash never return an error.
+func (h *nodeHasher) tag(t byte) {
+	h.buf[0] = t
+	h.h.Write(h.buf[:1])
+}
+
+// uint64 writes v as 8 bytes.
+func (h *nodeHasher) uint64(v uint64) {
+	binary.LittleEndian.PutUint64(h.buf[:], v)
+	h.h.Write(h.buf[:])
+}
+
+// string writes s prefixed with its length.
+func (h *nodeHasher) string(s string) {
+	h.uint64(uint64(len(s)))
+	h.h.Write([]byte(s))
+}
+
+// node writes node and its descendants.
+func (h *nodeHasher) node(node SchemaNode) {
+	switch n := node.(type) {
+	case nil:
+		h.tag(hashNil)
+
+	case *LiteralNode:
+		h.tag(hashLiteral)
+		h.value(n.value)
+
+	case *TypeNode:
+		h.tag(hashType)
+		h.string(n.typeName)
+
+	case *FunctionNode:
+		h.tag(hashFunction)
+		h.string(n.name)
+		h.uint64(uint64(len(n.arguments)))
+		for _, arg := range n.arguments {
+			h.value(arg)
+		}
+
+	case *ObjectNode:
+		h.tag(hashObject)
+		keys := make([]string, 0, len(n.properties))
+		for key := range n.properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		if len(n.entries) <= len(n.properties) {
+			h.uint64(uint64(len(keys)))
+			for _, key := range keys {
+				h.string(key)
+				h.node(n.properties[key])
+			}
+			break
+		}
+
+		// Under DuplicateKeyKeepAll every occurrence counts, in source order
+		h.uint64(uint64(len(n.entries)))
+		for _, key := range keys {
+			for _, value := range n.GetAll(key) {
+				h.string(key)
+				h.node(value)
+			}
+		}
+
+	case *ArrayNode:
+		h.tag(hashArray)
+		h.node(n.elementSchema)
+
+	case *ArrayDataNode:
+		h.tag(hashArrayData)
+		h.uint64(uint64(len(n.elements)))
+		for _, elem := range n.elements {
+			h.node(elem)
+		}
+
+	default:
+		h.tag(hashUnknownNode)
+		h.string(fmt.Sprintf("%T", node))
+		h.string(node.String())
+	}
+}
+
+// value writes a literal or argument value.
+func (h *nodeHasher) value(value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		h.tag(hashNil)
+	case string:
+		h.tag(hashString)
+		h.string(v)
+	case bool:
+		h.tag(hashBool)
+		if v {
+			h.tag(1)
+		} else {
+			h.tag(0)
+		}
+	case int64:
+		h.tag(hashInt64)
+		h.uint64(uint64(v))
+	case float64:
+		h.tag(hashFloat64)
+		if v == 0 {
+			v = 0 // -0 equals 0
+		}
+		h.uint64(math.Float64bits(v))
+	case *big.Int:
+		h.tag(hashBigInt)
+		if v == nil {
+			h.string("nil")
+		} else {
+			h.string(v.String())
+		}
+	case *big.Float:
+		h.tag(hashBigFloat)
+		switch {
+		case v == nil:
+			h.string("nil")
+		case v.Sign() == 0:
+			h.string("0") // -0 equals 0
+		default:
+			h.string(v.Text('p', 0)) // Normalized, so precision does not matter
+		}
+	case Decimal:
+		h.tag(hashDecimal)
+		h.string(v.Rat().RatString()) // Normalized, so 1.50 and 1.5 hash alike
+	case []byte:
+		h.tag(hashBytes)
+		h.string(string(v))
+	case time.Time:
+		h.tag(hashTime)
+		h.string(v.UTC().Format(time.RFC3339Nano))
+	default:
+		h.tag(hashOther)
+		h.string(fmt.Sprintf("%T", v))
+		h.string(fmt.Sprintf("%v", v))
+	}
+}
@@ -0,0 +1,230 @@
+package ast
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// cloneTestTree builds a tree that uses all six node types
+func cloneTestTree() *ObjectNode {
+	id := NewLiteralNode(big.NewInt(42), NewPosition(8, 1, 9))
+	id.SetRaw("42")
+	id.SetSpan(NewSpan(NewPosition(8, 1, 9), NewPosition(10, 1, 11)))
+	id.SetAnnotations(&Annotations{
+		Leading:  []Comment{{Text: "// identifier"}},
+		Metadata: map[string]interface{}{"description": "Primary key"},
+	})
+
+	root := NewOrderedObjectNode([]PropertyEntry{
+		{Key: "id", Value: id, KeyPosition: NewPosition(1, 1, 2)},
+		{Key: "data", Value: NewLiteralNode([]byte("raw"), Position{})},
+		{Key: "age", Value: NewFunctionNode("Integer", []interface{}{int64(0), big.NewInt(150)}, Position{})},
+		{Key: "tags", Value: NewArrayNode(NewTypeNode("String", Position{}), Position{})},
+		{Key: "items", Value: NewArrayDataNode([]SchemaNode{NewLiteralNode(1.5, Position{}), NewLiteralNode(nil, Position{})}, Position{})},
+	}, NewPosition(0, 1, 1))
+	root.SetSpan(NewSpan(NewPosition(0, 1, 1), NewPosition(60, 1, 61)))
+	return root
+}
+
+func TestClone(t *testing.T) {
+	root := cloneTestTree()
+	clone, ok := Clone(root).(*ObjectNode)
+	if !ok {
+		t.Fatalf("Clone() = %T, want *ObjectNode", Clone(root))
+	}
+
+	if clone == root || clone.String() != root.String() {
+		t.Fatalf("Clone() = %s, want a copy of %s", clone, root)
+	}
+	if clone.Span() != root.Span() {
+		t.Errorf("Span() = %v, want %v", clone.Span(), root.Span())
+	}
+	if Hash(clone) != Hash(root) {
+		t.Error("Expected the clone to hash like the original")
+	}
+
+	// Every node is copied, keeping positions, raw lexemes and annotations
+	Traverse(root, func(node SchemaNode, path Path) WalkAction {
+		if len(path) == 0 {
+			return WalkContinue
+		}
+		var copied SchemaNode
+		Traverse(clone, func(other SchemaNode, otherPath Path) WalkAction {
+			if otherPath.String() == path.String() {
+				copied = other
+				return WalkStop
+			}
+			return WalkContinue
+		}, nil)
+		if copied == nil || copied == node {
+			t.Errorf("%s: not copied", path)
+		} else if copied.(Spanned).Span() != node.(Spanned).Span() || copied.String() != node.String() {
+			t.Errorf("%s: copy %s at %v, want %s at %v", path, copied, copied.(Spanned).Span(), node, node.(Spanned).Span())
+		}
+		return WalkContinue
+	}, nil)
+
+	id, _ := clone.GetProperty("id")
+	if id.(*LiteralNode).Raw() != "42" {
+		t.Errorf("Raw() = %q, want 42", id.(*LiteralNode).Raw())
+	}
+	if pos, _ := clone.KeyPosition("id"); pos != NewPosition(1, 1, 2) {
+		t.Errorf("KeyPosition(id) = %v", pos)
+	}
+	if clone.PropertyEntries()[0].Value != id {
+		t.Error("Expected entries and properties to share the cloned value")
+	}
+}
+
+func TestClone_IsIndependent(t *testing.T) {
+	root := cloneTestTree()
+	clone := Clone(root).(*ObjectNode)
+
+	// Mutate everything mutable in the clone
+	id, _ := clone.GetProperty("id")
+	id.(*LiteralNode).Annotations().Set("description", "changed")
+	id.(*LiteralNode).Annotations().Leading[0].Text = "// changed"
+	id.(*LiteralNode).Value().(*big.Int).SetInt64(7)
+	data, _ := clone.GetProperty("data")
+	data.(*LiteralNode).Value().([]byte)[0] = 'X'
+	age, _ := clone.GetProperty("age")
+	age.(*FunctionNode).Arguments()[1].(*big.Int).SetInt64(0)
+	clone.SetSpan(Span{})
+
+	origID, _ := root.GetProperty("id")
+	origAnnotations := origID.(Annotated).Annotations()
+	if description, _ := origAnnotations.Get("description"); description != "Primary key" {
+		t.Errorf("original metadata changed to %v", description)
+	}
+	if origAnnotations.Leading[0].Text != "// identifier" {
+		t.Errorf("original comment changed to %q", origAnnotations.Leading[0].Text)
+	}
+	if root.String() != cloneTestTree().String() {
+		t.Errorf("original changed to %s", root)
+	}
+	if !root.Span().IsValid() {
+		t.Error("original span changed")
+	}
+
+	// Releasing the clone's pooled nodes leaves the original intact
+	ReleaseLiteralNode(id.(*LiteralNode))
+	ReleaseObjectNode(clone)
+	if root.Len() != 5 || origID.(*LiteralNode).Value() == nil {
+		t.Error("original affected by releasing the clone")
+	}
+	bigFloat := NewLiteralNode(big.NewFloat(1.5), Position{})
+	Clone(bigFloat).(*LiteralNode).Value().(*big.Float).SetInt64(2)
+	if bigFloat.String() != "1.5" {
+		t.Errorf("original big float changed to %s", bigFloat)
+	}
+}
+
+func TestClone_Objects(t *testing.T) {
+	keepAll, _ := NewObjectNodeWithPolicy([]PropertyEntry{
+		{Key: "a", Value: NewLiteralNode(int64(1), Position{}), KeyPosition: NewPosition(1, 1, 2)},
+		{Key: "a", Value: NewLiteralNode(int64(2), Position{}), KeyPosition: NewPosition(9, 1, 10)},
+	}, Position{}, DuplicateKeyKeepAll)
+
+	clone := Clone(keepAll).(*ObjectNode)
+	if len(clone.GetAll("a")) != 2 || clone.String() != keepAll.String() {
+		t.Errorf("Clone() = %s, want %s", clone, keepAll)
+	}
+	if len(clone.Duplicates()) != 1 || len(clone.Duplicates()[0].Positions) != 2 {
+		t.Errorf("Duplicates() = %v", clone.Duplicates())
+	}
+	clone.Duplicates()[0].Positions[0] = Position{}
+	if keepAll.Duplicates()[0].Positions[0] != NewPosition(1, 1, 2) {
+		t.Error("original duplicates changed")
+	}
+
+	fromMap := NewObjectNode(map[string]SchemaNode{"b": NewTypeNode("UUID", Position{}), "a": NewTypeNode("Email", Position{})}, Position{})
+	if got := Clone(fromMap).String(); got != fromMap.String() {
+		t.Errorf("Clone() = %s, want %s", got, fromMap)
+	}
+
+	if Clone(nil) != nil {
+		t.Error("Clone(nil) should be nil")
+	}
+}
+
+func TestHash(t *testing.T) {
+	onePointFive, _ := ParseDecimal("1.5")
+	oneFifty, _ := ParseDecimal("1.50")
+	stamp := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	ordered := NewOrderedObjectNode([]PropertyEntry{
+		{Key: "b", Value: NewLiteralNode(int64(2), NewPosition(5, 1, 6))},
+		{Key: "a", Value: NewLiteralNode(onePointFive, NewPosition(12, 1, 13))},
+	}, NewPosition(0, 1, 1))
+	ordered.SetAnnotations(&Annotations{Metadata: map[string]interface{}{"title": "x"}})
+	fromMap := NewObjectNode(map[string]SchemaNode{
+		"a": NewLiteralNode(oneFifty, Position{}),
+		"b": NewLiteralNode(int64(2), Position{}),
+	}, Position{})
+
+	if Hash(ordered) != Hash(fromMap) {
+		t.Error("Expected positions, annotations, key order and decimal scale not to affect the hash")
+	}
+
+	equal := []struct {
+		name string
+		a, b SchemaNode
+	}{
+		{"negative zero", NewLiteralNode(0.0, Position{}), NewLiteralNode(math.Copysign(0, -1), Position{})},
+		{"time zones", NewLiteralNode(stamp, Position{}), NewLiteralNode(stamp.In(time.FixedZone("", 3600)), Position{})},
+		{"big float precision", NewLiteralNode(big.NewFloat(1.5).SetPrec(200), Position{}), NewLiteralNode(big.NewFloat(1.5), Position{})},
+	}
+	for _, tt := range equal {
+		if Hash(tt.a) != Hash(tt.b) {
+			t.Errorf("%s: expected equal hashes", tt.name)
+		}
+	}
+
+	distinct := []SchemaNode{
+		nil,
+		NewLiteralNode(nil, Position{}),
+		NewLiteralNode("1", Position{}),
+		NewLiteralNode(int64(1), Position{}),
+		NewLiteralNode(1.0, Position{}),
+		NewLiteralNode(big.NewInt(1), Position{}),
+		NewLiteralNode(big.NewFloat(1), Position{}),
+		NewLiteralNode(onePointFive, Position{}),
+		NewLiteralNode([]byte("1"), Position{}),
+		NewLiteralNode(true, Position{}),
+		NewLiteralNode(false, Position{}),
+		NewLiteralNode(stamp, Position{}),
+		NewTypeNode("UUID", Position{}),
+		NewTypeNode("Email", Position{}),
+		NewFunctionNode("Integer", []interface{}{int64(1), "+"}, Position{}),
+		NewFunctionNode("Integer", []interface{}{int64(1)}, Position{}),
+		NewObjectNode(map[string]SchemaNode{}, Position{}),
+		NewObjectNode(map[string]SchemaNode{"ab": NewLiteralNode("", Position{})}, Position{}),
+		NewObjectNode(map[string]SchemaNode{"a": NewLiteralNode("b", Position{})}, Position{}),
+		NewArrayNode(NewTypeNode("UUID", Position{}), Position{}),
+		NewArrayDataNode(nil, Position{}),
+		NewArrayDataNode([]SchemaNode{NewTypeNode("UUID", Position{})}, Position{}),
+		fromMap,
+	}
+	seen := make(map[uint64]int)
+	for i, node := range distinct {
+		h := Hash(node)
+		if j, ok := seen[h]; ok {
+			t.Errorf("Hash collision between %v and %v", distinct[j], node)
+		}
+		seen[h] = i
+	}
+}
+
+func TestHash_Stable(t *testing.T) {
+	// The hash is part of the API for persistent caches; it must not change
+	// between runs or releases without notice.
+	node := NewObjectNode(map[string]SchemaNode{
+		"id":   NewTypeNode("UUID", Position{}),
+		"tags": NewArrayDataNode([]SchemaNode{NewLiteralNode("a", Position{})}, Position{}),
+	}, Position{})
+	if got, want := Hash(node), uint64(0x65d35b1bd8fcdcb4); got != want {
+		t.Errorf("Hash() = %#x, want %#x", got, want)
+	}
+}
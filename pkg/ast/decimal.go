@@ -0,0 +1,158 @@
+package ast
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an arbitrary-precision decimal number with the value
+// unscaled × 10^-scale. It keeps trailing zeros, so "1.50" and "1.5" are
+// distinct Decimals that compare equal with Cmp. The zero value is 0.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int32
+}
+
+// MaxDecimalScale bounds the scale of a Decimal in both directions. Larger
+// scales would make Rat, String and Cmp allocate powers of ten with
+// billions of digits, so ParseDecimal rejects them.
+const MaxDecimalScale = 10000
+
+// NewDecimal creates a Decimal with the value unscaled × 10^-scale. It
+// panics if scale is beyond ±MaxDecimalScale.
+func NewDecimal(unscaled *big.Int, scale int32) Decimal {
+	if scale > MaxDecimalScale || scale < -MaxDecimalScale {
+		panic(fmt.Sprintf("ast: NewDecimal scale %d out of range", scale))
+	}
+	return Decimal{unscaled: new(big.Int).Set(unscaled), scale: scale}
+}
+
+// ParseDecimal parses a decimal literal such as "123.4500", "-0.5" or
+// "6.02e23". It accepts an optional sign, digits with an optional fraction,
+// and an optional exponent. Literals whose scale would exceed
+// ±MaxDecimalScale are rejected.
+func ParseDecimal(s string) (Decimal, error) {
+	mantissa, exponent := s, ""
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		mantissa, exponent = s[:i], s[i+1:]
+		if exponent == "" {
+			return Decimal{}, fmt.Errorf("invalid decimal %q: missing exponent", s)
+		}
+	}
+
+	digits := mantissa
+	if digits != "" && (digits[0] == '+' || digits[0] == '-') {
+		digits = digits[1:]
+	}
+	intPart, fracPart := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		intPart, fracPart = digits[:i], digits[i+1:]
+	}
+	if intPart == "" && fracPart == "" {
+		return Decimal{}, fmt.Errorf("invalid decimal %q: no digits", s)
+	}
+	for _, part := range []string{intPart, fracPart} {
+		for i := 0; i < len(part); i++ {
+			if part[i] < '0' || part[i] > '9' {
+				return Decimal{}, fmt.Errorf("invalid decimal %q: unexpected %q", s, part[i])
+			}
+		}
+	}
+
+	scale := int64(len(fracPart))
+	if exponent != "" {
+		exp, err := strconv.ParseInt(exponent, 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("invalid decimal %q: bad exponent", s)
+		}
+		scale -= exp
+	}
+	if scale > MaxDecimalScale || scale < -MaxDecimalScale {
+		return Decimal{}, fmt.Errorf("invalid decimal %q: exponent out of range", s)
+	}
+
+	unscaled, ok := new(big.Int).SetString(intPart+fracPart, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	if mantissa[0] == '-' {
+		unscaled.Neg(unscaled)
+	}
+	return Decimal{unscaled: unscaled, scale: int32(scale)}, nil
+}
+
+// Unscaled returns a copy of the unscaled value.
+func (d Decimal) Unscaled() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(d.unscaled)
+}
+
+// Scale returns the number of digits after the decimal point. A negative
+// scale multiplies the unscaled value by a power of ten.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d.
+func (d Decimal) Sign() int {
+	if d.unscaled == nil {
+		return 0
+	}
+	return d.unscaled.Sign()
+}
+
+// Rat returns d as an exact rational number.
+func (d Decimal) Rat() *big.Rat {
+	r := new(big.Rat).SetInt(d.Unscaled())
+	pow := new(big.Rat).SetInt(pow10(d.scale))
+	if d.scale >= 0 {
+		return r.Quo(r, pow)
+	}
+	return r.Mul(r, pow)
+}
+
+// Cmp compares d and other numerically, ignoring scale, and returns -1, 0
+// or +1.
+func (d Decimal) Cmp(other Decimal) int {
+	if d.scale == other.scale {
+		return d.Unscaled().Cmp(other.Unscaled())
+	}
+	return d.Rat().Cmp(other.Rat())
+}
+
+// String returns d in plain notation with all of its scale, such as
+// "123.4500". Decimals with a negative scale use an exponent ("12e3").
+func (d Decimal) String() string {
+	digits := d.Unscaled().String()
+	sign := ""
+	if digits[0] == '-' {
+		sign, digits = "-", digits[1:]
+	}
+
+	switch {
+	case d.scale == 0:
+		return sign + digits
+	case d.scale < 0:
+		return fmt.Sprintf("%s%se%d", sign, digits, -int64(d.scale))
+	}
+
+	scale := int(d.scale)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	point := len(digits) - scale
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// pow10 returns 10^|n|.
+func pow10(n int32) *big.Int {
+	exp := int64(n)
+	if exp < 0 {
+		exp = -exp
+	}
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(exp), nil)
+}
@@ -0,0 +1,164 @@
+package ast
+
+import "fmt"
+
+// DuplicateKeyPolicy decides how NewObjectNodeWithPolicy handles a key that
+// appears more than once in an object.
+type DuplicateKeyPolicy int
+
+// Duplicate key policies.
+const (
+	// DuplicateKeyLastWins keeps the last value in the place of the first occurrence
+	DuplicateKeyLastWins DuplicateKeyPolicy = iota
+
+	// DuplicateKeyFirstWins keeps the first value and ignores later ones
+	DuplicateKeyFirstWins
+
+	// DuplicateKeyReject rejects the object with a *DuplicateKeyError
+	DuplicateKeyReject
+
+	// DuplicateKeyKeepAll keeps every occurrence as a separate entry; lookups
+	// by name return the last value and GetAll returns them all
+	DuplicateKeyKeepAll
+)
+
+// String returns the policy name.
+func (p DuplicateKeyPolicy) String() string {
+	switch p {
+	case DuplicateKeyLastWins:
+		return "last-wins"
+	case DuplicateKeyFirstWins:
+		return "first-wins"
+	case DuplicateKeyReject:
+		return "error"
+	case DuplicateKeyKeepAll:
+		return "keep-all"
+	default:
+		return fmt.Sprintf("DuplicateKeyPolicy(%d)", int(p))
+	}
+}
+
+// DuplicateKey records a key that appears more than once in an object, with
+// the key position of every occurrence in source order.
+type DuplicateKey struct {
+	Key       string
+	Positions []Position
+}
+
+// DuplicateKeyError is returned by NewObjectNodeWithPolicy under
+// DuplicateKeyReject for the first repeated key.
+type DuplicateKeyError struct {
+	Key       string
+	First     Position // Key position of the first occurrence
+	Duplicate Position // Key position of the repeat
+}
+
+// Error implements the error interface.
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("duplicate key %q at %s (first defined at %s)", e.Key, e.Duplicate, e.First)
+}
+
+// NewObjectNodeWithPolicy creates an ordered object node, resolving repeated
+// keys with policy. All policies except DuplicateKeyReject build the node and
+// record the repeats, which are available from Duplicates.
+//
+// Example:
+//
+//	node, err := NewObjectNodeWithPolicy(entries, pos, DuplicateKeyReject)
+//	var dup *DuplicateKeyError
+//	if errors.As(err, &dup) {
+//	    // report dup.Key at dup.Duplicate
+//	}
+func NewObjectNodeWithPolicy(entries []PropertyEntry, pos Position, policy DuplicateKeyPolicy) (*ObjectNode, error) {
+	if policy == DuplicateKeyReject {
+		first := make(map[string]Position, len(entries))
+		for _, entry := range entries {
+			if firstPos, ok := first[entry.Key]; ok {
+				return nil, &DuplicateKeyError{Key: entry.Key, First: firstPos, Duplicate: entry.KeyPosition}
+			}
+			first[entry.Key] = entry.KeyPosition
+		}
+	}
+	return buildObjectNode(entries, pos, policy), nil
+}
+
+// buildObjectNode creates an ordered object node from a pooled ObjectNode.
+func buildObjectNode(entries []PropertyEntry, pos Position, policy DuplicateKeyPolicy) *ObjectNode {
+	properties := make(map[string]SchemaNode, len(entries))
+	ordered := make([]PropertyEntry, 0, len(entries))
+	index := make(map[string]int, len(entries))
+	var duplicates []DuplicateKey
+	var duplicateIndex map[string]int // Key → index in duplicates
+
+	for _, entry := range entries {
+		i, seen := index[entry.Key]
+		if !seen {
+			index[entry.Key] = len(ordered)
+			ordered = append(ordered, entry)
+			properties[entry.Key] = entry.Value
+			continue
+		}
+
+		if d, ok := duplicateIndex[entry.Key]; ok {
+			duplicates[d].Positions = append(duplicates[d].Positions, entry.KeyPosition)
+		} else {
+			if duplicateIndex == nil {
+				duplicateIndex = make(map[string]int)
+			}
+			duplicateIndex[entry.Key] = len(duplicates)
+			// Under last-wins the entry already holds the latest occurrence, so
+			// the first position comes from the duplicate record
+			first := ordered[i].KeyPosition
+			duplicates = append(duplicates, DuplicateKey{Key: entry.Key, Positions: []Position{first, entry.KeyPosition}})
+		}
+
+		switch policy {
+		case DuplicateKeyFirstWins:
+			// Keep the first value
+		case DuplicateKeyKeepAll:
+			index[entry.Key] = len(ordered)
+			ordered = append(ordered, entry)
+			properties[entry.Key] = entry.Value
+		default:
+			ordered[i].Value = entry.Value
+			ordered[i].KeyPosition = entry.KeyPosition
+			ordered[i].SeparatorPosition = entry.SeparatorPosition
+			properties[entry.Key] = entry.Value
+		}
+	}
+
+	// nolint:errcheck // sync.Pool.Get() doesn't return an error
+	n := objectNodePool.Get().(*ObjectNode)
+	n.properties = properties
+	n.entries = ordered
+	n.index = index
+	n.duplicates = duplicates
+	n.position = pos
+	n.end = ZeroPosition()
+	n.annotations = nil
+	return n
+}
+
+// Duplicates returns the keys that appeared more than once when the node was
+// built, or nil.
+func (n *ObjectNode) Duplicates() []DuplicateKey {
+	return n.duplicates
+}
+
+// GetAll returns every value recorded for a property: all occurrences under
+// DuplicateKeyKeepAll, otherwise at most one.
+func (n *ObjectNode) GetAll(name string) []SchemaNode {
+	if len(n.entries) > len(n.properties) {
+		var values []SchemaNode
+		for _, entry := range n.entries {
+			if entry.Key == name {
+				values = append(values, entry.Value)
+			}
+		}
+		return values
+	}
+	if value, ok := n.properties[name]; ok {
+		return []SchemaNode{value}
+	}
+	return nil
+}
@@ -8,9 +8,11 @@ import (
 // FunctionNode represents function-based validation with arguments.
 // Examples: Integer(1, 100), String(5+), Enum("M", "F", "O")
 type FunctionNode struct {
-	name      string        // Function name (Integer, String, Enum, etc.)
-	arguments []interface{} // Arguments (literals or special symbols like "+")
-	position  Position
+	name        string        // Function name (Integer, String, Enum, etc.)
+	arguments   []interface{} // Arguments (literals or special symbols like "+")
+	position    Position
+	end         Position     // End of the source range; see Span
+	annotations *Annotations // Comments and metadata; nil when there are none
 }
 
 // NewFunctionNode creates a new function node.
@@ -43,6 +45,28 @@ func (n *FunctionNode) Position() Position {
 	return n.position
 }
 
+// Span returns the source range of the node.
+func (n *FunctionNode) Span() Span {
+	return Span{Start: n.position, End: n.end}
+}
+
+// SetSpan sets the source range of the node, including its position.
+// Parsers call it once the end of the node is known, before sharing the node.
+func (n *FunctionNode) SetSpan(span Span) {
+	n.position = span.Start
+	n.end = span.End
+}
+
+// Annotations returns the comments and metadata attached to the node, or nil.
+func (n *FunctionNode) Annotations() *Annotations {
+	return n.annotations
+}
+
+// SetAnnotations attaches comments and metadata to the node.
+func (n *FunctionNode) SetAnnotations(annotations *Annotations) {
+	n.annotations = annotations
+}
+
 // Accept implements the visitor pattern.
 func (n *FunctionNode) Accept(visitor Visitor) error {
 	return visitor.VisitFunction(n)
@@ -1,14 +1,24 @@
 package ast
 
 import (
+	"encoding/hex"
 	"fmt"
+	"math/big"
 	"sync"
+	"time"
 )
 
 // LiteralNode represents an exact match validation (literal values from JSON/XML/etc.).
+//
+// Values are string, int64, float64, bool or nil, or for values those types
+// cannot hold exactly, uint64, *big.Int, *big.Float, Decimal, []byte or
+// time.Time.
 type LiteralNode struct {
-	value    interface{} // string, int64, float64, bool, or nil
-	position Position
+	value       interface{} // See the type comment for the supported types
+	raw         string      // Original lexeme; empty when unknown
+	position    Position
+	end         Position     // End of the source range; see Span
+	annotations *Annotations // Comments and metadata; nil when there are none
 }
 
 // literalNodePool reduces allocation overhead by reusing LiteralNode objects.
@@ -25,7 +35,10 @@ func NewLiteralNode(value interface{}, pos Position) *LiteralNode {
 	// nolint:errcheck // sync.Pool.Get() doesn't return an error
 	n := literalNodePool.Get().(*LiteralNode)
 	n.value = value
+	n.raw = ""
 	n.position = pos
+	n.end = ZeroPosition()
+	n.annotations = nil
 	return n
 }
 
@@ -36,8 +49,9 @@ func ReleaseLiteralNode(n *LiteralNode) {
 	if n == nil {
 		return
 	}
-	// Clear value to prevent memory leaks
+	// Clear value and annotations to prevent memory leaks
 	n.value = nil
+	n.annotations = nil
 	literalNodePool.Put(n)
 }
 
@@ -51,11 +65,45 @@ func (n *LiteralNode) Value() interface{} {
 	return n.value
 }
 
+// Raw returns the literal as written in the source, such as "1.50" or
+// "0x1F", or "" if the parser did not record it.
+func (n *LiteralNode) Raw() string {
+	return n.raw
+}
+
+// SetRaw records the literal as written in the source, so that tools can
+// reproduce it exactly even when the value normalizes it.
+func (n *LiteralNode) SetRaw(raw string) {
+	n.raw = raw
+}
+
 // Position returns the source position.
 func (n *LiteralNode) Position() Position {
 	return n.position
 }
 
+// Span returns the source range of the node.
+func (n *LiteralNode) Span() Span {
+	return Span{Start: n.position, End: n.end}
+}
+
+// SetSpan sets the source range of the node, including its position.
+// Parsers call it once the end of the node is known, before sharing the node.
+func (n *LiteralNode) SetSpan(span Span) {
+	n.position = span.Start
+	n.end = span.End
+}
+
+// Annotations returns the comments and metadata attached to the node, or nil.
+func (n *LiteralNode) Annotations() *Annotations {
+	return n.annotations
+}
+
+// SetAnnotations attaches comments and metadata to the node.
+func (n *LiteralNode) SetAnnotations(annotations *Annotations) {
+	n.annotations = annotations
+}
+
 // Accept implements the visitor pattern.
 func (n *LiteralNode) Accept(visitor Visitor) error {
 	return visitor.VisitLiteral(n)
@@ -74,6 +122,16 @@ func (n *LiteralNode) String() string {
 		return fmt.Sprintf("%d", v)
 	case float64:
 		return fmt.Sprintf("%g", v)
+	case *big.Int:
+		return v.String()
+	case *big.Float:
+		return v.Text('g', -1)
+	case Decimal:
+		return v.String()
+	case []byte:
+		return "0x" + hex.EncodeToString(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
 	default:
 		return fmt.Sprintf("%v", v)
 	}
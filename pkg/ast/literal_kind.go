@@ -0,0 +1,363 @@
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+// LiteralKind classifies the value of a LiteralNode.
+type LiteralKind int
+
+const (
+	// LiteralNull is a nil value
+	LiteralNull LiteralKind = iota
+
+	// LiteralString is a string value
+	LiteralString
+
+	// LiteralBool is a bool value
+	LiteralBool
+
+	// LiteralInt is an int64 value; other Go integer types are reported as
+	// LiteralInt too
+	LiteralInt
+
+	// LiteralFloat is a float64 (or float32) value
+	LiteralFloat
+
+	// LiteralBigInt is a *big.Int value
+	LiteralBigInt
+
+	// LiteralBigFloat is a *big.Float value, such as a float literal beyond
+	// the float64 range
+	LiteralBigFloat
+
+	// LiteralDecimal is a Decimal value
+	LiteralDecimal
+
+	// LiteralBytes is a []byte value
+	LiteralBytes
+
+	// LiteralTime is a time.Time value
+	LiteralTime
+
+	// LiteralOther is a value of any other type
+	LiteralOther
+)
+
+// String returns the string representation of the literal kind.
+func (k LiteralKind) String() string {
+	switch k {
+	case LiteralNull:
+		return "Null"
+	case LiteralString:
+		return "String"
+	case LiteralBool:
+		return "Bool"
+	case LiteralInt:
+		return "Int"
+	case LiteralFloat:
+		return "Float"
+	case LiteralBigInt:
+		return "BigInt"
+	case LiteralBigFloat:
+		return "BigFloat"
+	case LiteralDecimal:
+		return "Decimal"
+	case LiteralBytes:
+		return "Bytes"
+	case LiteralTime:
+		return "Time"
+	case LiteralOther:
+		return "Other"
+	default:
+		return "Unknown"
+	}
+}
+
+// IsNumeric returns true for integer, float, big integer, big float and
+// decimal kinds.
+func (k LiteralKind) IsNumeric() bool {
+	switch k {
+	case LiteralInt, LiteralFloat, LiteralBigInt, LiteralBigFloat, LiteralDecimal:
+		return true
+	default:
+		return false
+	}
+}
+
+// Errors returned by the numeric accessors of LiteralNode, wrapped with the
+// literal and the requested type. Test for them with errors.Is.
+var (
+	// ErrLiteralType reports a literal that is not a number
+	ErrLiteralType = errors.New("literal is not a number")
+
+	// ErrLiteralRange reports a number outside the range of the requested type
+	ErrLiteralRange = errors.New("number out of range")
+
+	// ErrLiteralFraction reports a number with a fractional part where an
+	// integer was requested
+	ErrLiteralFraction = errors.New("number is not an integer")
+)
+
+// Kind returns the kind of the literal's value.
+func (n *LiteralNode) Kind() LiteralKind {
+	switch n.value.(type) {
+	case nil:
+		return LiteralNull
+	case string:
+		return LiteralString
+	case bool:
+		return LiteralBool
+	case int64, int, int8, int16, int32, uint, uint8, uint16, uint32, uint64:
+		return LiteralInt
+	case float64, float32:
+		return LiteralFloat
+	case *big.Int:
+		return LiteralBigInt
+	case *big.Float:
+		return LiteralBigFloat
+	case Decimal:
+		return LiteralDecimal
+	case []byte:
+		return LiteralBytes
+	case time.Time:
+		return LiteralTime
+	default:
+		return LiteralOther
+	}
+}
+
+// IsNull returns true if the literal is null.
+func (n *LiteralNode) IsNull() bool {
+	return n.value == nil
+}
+
+// AsString returns the value if the literal is a string.
+func (n *LiteralNode) AsString() (string, bool) {
+	s, ok := n.value.(string)
+	return s, ok
+}
+
+// AsBool returns the value if the literal is a bool.
+func (n *LiteralNode) AsBool() (bool, bool) {
+	b, ok := n.value.(bool)
+	return b, ok
+}
+
+// AsBytes returns the value if the literal is a []byte.
+func (n *LiteralNode) AsBytes() ([]byte, bool) {
+	b, ok := n.value.([]byte)
+	return b, ok
+}
+
+// AsTime returns the value if the literal is a time.Time.
+func (n *LiteralNode) AsTime() (time.Time, bool) {
+	t, ok := n.value.(time.Time)
+	return t, ok
+}
+
+// AsInt64 converts a numeric literal to int64. Floats and decimals convert
+// only when they hold an integer; values that do not fit report
+// ErrLiteralRange.
+func (n *LiteralNode) AsInt64() (int64, error) {
+	if v, ok := n.value.(int64); ok {
+		return v, nil
+	}
+	i, err := n.AsBigInt()
+	if err != nil {
+		return 0, err
+	}
+	if !i.IsInt64() {
+		return 0, n.conversionError("int64", ErrLiteralRange)
+	}
+	return i.Int64(), nil
+}
+
+// AsFloat64 converts a numeric literal to float64, rounding to the nearest
+// float64 if needed. Values beyond the float64 range report ErrLiteralRange.
+func (n *LiteralNode) AsFloat64() (float64, error) {
+	switch v := n.value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case *big.Int:
+		if v == nil {
+			break
+		}
+		f, _ := new(big.Float).SetInt(v).Float64()
+		if math.IsInf(f, 0) {
+			return 0, n.conversionError("float64", ErrLiteralRange)
+		}
+		return f, nil
+	case *big.Float:
+		if v == nil {
+			break
+		}
+		f, _ := v.Float64()
+		if math.IsInf(f, 0) {
+			return 0, n.conversionError("float64", ErrLiteralRange)
+		}
+		return f, nil
+	case Decimal:
+		f, _ := v.Rat().Float64()
+		if math.IsInf(f, 0) {
+			return 0, n.conversionError("float64", ErrLiteralRange)
+		}
+		return f, nil
+	default:
+		if i, ok := n.smallInt(); ok {
+			return i.float64(), nil
+		}
+	}
+	return 0, n.conversionError("float64", ErrLiteralType)
+}
+
+// AsBigInt converts a numeric literal to a new *big.Int. Floats and decimals
+// convert only when they hold an integer.
+func (n *LiteralNode) AsBigInt() (*big.Int, error) {
+	switch v := n.value.(type) {
+	case *big.Int:
+		if v != nil {
+			return new(big.Int).Set(v), nil
+		}
+	case float64:
+		return n.floatToBigInt(v)
+	case float32:
+		return n.floatToBigInt(float64(v))
+	case *big.Float:
+		if v == nil {
+			break
+		}
+		if v.IsInf() {
+			return nil, n.conversionError("integer", ErrLiteralRange)
+		}
+		if !v.IsInt() {
+			return nil, n.conversionError("integer", ErrLiteralFraction)
+		}
+		i, _ := v.Int(nil)
+		return i, nil
+	case Decimal:
+		r := v.Rat()
+		if !r.IsInt() {
+			return nil, n.conversionError("integer", ErrLiteralFraction)
+		}
+		return new(big.Int).Set(r.Num()), nil
+	default:
+		if i, ok := n.smallInt(); ok {
+			return i.bigInt(), nil
+		}
+	}
+	return nil, n.conversionError("integer", ErrLiteralType)
+}
+
+// AsDecimal converts a numeric literal to a Decimal. Floats, including
+// *big.Float, convert through their shortest decimal representation, so 0.1
+// becomes exactly 0.1.
+func (n *LiteralNode) AsDecimal() (Decimal, error) {
+	switch v := n.value.(type) {
+	case Decimal:
+		return v, nil
+	case float64:
+		return n.floatToDecimal(v, 64)
+	case float32:
+		return n.floatToDecimal(float64(v), 32)
+	case *big.Float:
+		if v == nil {
+			break
+		}
+		if v.IsInf() {
+			return Decimal{}, n.conversionError("decimal", ErrLiteralRange)
+		}
+		d, err := ParseDecimal(v.Text('g', -1))
+		if err != nil {
+			return Decimal{}, n.conversionError("decimal", ErrLiteralRange)
+		}
+		return d, nil
+	}
+
+	i, err := n.AsBigInt()
+	if err != nil {
+		return Decimal{}, n.conversionError("decimal", ErrLiteralType)
+	}
+	return Decimal{unscaled: i}, nil
+}
+
+// floatToBigInt converts an integral float to a *big.Int.
+func (n *LiteralNode) floatToBigInt(f float64) (*big.Int, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, n.conversionError("integer", ErrLiteralRange)
+	}
+	if f != math.Trunc(f) {
+		return nil, n.conversionError("integer", ErrLiteralFraction)
+	}
+	i, _ := big.NewFloat(f).Int(nil)
+	return i, nil
+}
+
+// floatToDecimal converts a float of the given bit size to a Decimal.
+func (n *LiteralNode) floatToDecimal(f float64, bitSize int) (Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}, n.conversionError("decimal", ErrLiteralRange)
+	}
+	return ParseDecimal(strconv.FormatFloat(f, 'g', -1, bitSize))
+}
+
+// conversionError wraps err with the literal and the requested type.
+func (n *LiteralNode) conversionError(target string, err error) error {
+	return fmt.Errorf("cannot convert %s literal %s to %s: %w", n.Kind(), n.String(), target, err)
+}
+
+// smallInteger holds a Go integer value of any size up to 64 bits.
+type smallInteger struct {
+	signed   int64
+	unsigned uint64
+	isSigned bool
+}
+
+func (i smallInteger) float64() float64 {
+	if i.isSigned {
+		return float64(i.signed)
+	}
+	return float64(i.unsigned)
+}
+
+func (i smallInteger) bigInt() *big.Int {
+	if i.isSigned {
+		return big.NewInt(i.signed)
+	}
+	return new(big.Int).SetUint64(i.unsigned)
+}
+
+// smallInt returns the value if it is a Go integer type.
+func (n *LiteralNode) smallInt() (smallInteger, bool) {
+	switch v := n.value.(type) {
+	case int64:
+		return smallInteger{signed: v, isSigned: true}, true
+	case int:
+		return smallInteger{signed: int64(v), isSigned: true}, true
+	case int8:
+		return smallInteger{signed: int64(v), isSigned: true}, true
+	case int16:
+		return smallInteger{signed: int64(v), isSigned: true}, true
+	case int32:
+		return smallInteger{signed: int64(v), isSigned: true}, true
+	case uint:
+		return smallInteger{unsigned: uint64(v)}, true
+	case uint8:
+		return smallInteger{unsigned: uint64(v)}, true
+	case uint16:
+		return smallInteger{unsigned: uint64(v)}, true
+	case uint32:
+		return smallInteger{unsigned: uint64(v)}, true
+	case uint64:
+		return smallInteger{unsigned: v}, true
+	default:
+		return smallInteger{}, false
+	}
+}
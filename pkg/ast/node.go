@@ -31,3 +31,21 @@ type SchemaNode interface {
 	// Position returns the source location for error messages
 	Position() Position
 }
+
+// Spanned is implemented by nodes that know their source range. All nodes in
+// this package implement it; check for it before relying on spans of nodes
+// defined elsewhere.
+type Spanned interface {
+	// Span returns the source range of the node; the end is zero unless the
+	// parser recorded it with SetSpan
+	Span() Span
+}
+
+// Annotated is implemented by nodes that can carry comments and metadata.
+// All nodes in this package implement it; check for it before reading the
+// annotations of nodes defined elsewhere.
+type Annotated interface {
+	// Annotations returns the comments and metadata attached to the node,
+	// or nil if there are none
+	Annotations() *Annotations
+}
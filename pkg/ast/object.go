@@ -8,9 +8,29 @@ import (
 )
 
 // ObjectNode represents object/map validation with property schemas.
+//
+// Properties keep their source order when the node is built with
+// NewOrderedObjectNode; nodes built from a map order their keys
+// alphabetically. Lookups by name go through a map either way.
 type ObjectNode struct {
-	properties map[string]SchemaNode // Property name → schema
-	position   Position
+	properties  map[string]SchemaNode // Property name → schema
+	entries     []PropertyEntry       // Properties in source order; nil when built from a map
+	index       map[string]int        // Property name → index in entries of the value in properties
+	duplicates  []DuplicateKey        // Repeated keys, in order of first occurrence
+	position    Position
+	end         Position     // End of the source range; see Span
+	annotations *Annotations // Comments and metadata; nil when there are none
+}
+
+// PropertyEntry is a single object property with the positions of its key
+// and of the separator between key and value (the ':' in JSON, '=' in
+// properties files). Either position may be zero when the source format or
+// the constructor does not provide it.
+type PropertyEntry struct {
+	Key               string
+	Value             SchemaNode
+	KeyPosition       Position
+	SeparatorPosition Position
 }
 
 // objectNodePool reduces allocation overhead by reusing ObjectNode objects.
@@ -27,10 +47,23 @@ func NewObjectNode(properties map[string]SchemaNode, pos Position) *ObjectNode {
 	// nolint:errcheck // sync.Pool.Get() doesn't return an error
 	n := objectNodePool.Get().(*ObjectNode)
 	n.properties = properties
+	n.entries = nil
+	n.index = nil
+	n.duplicates = nil
 	n.position = pos
+	n.end = ZeroPosition()
+	n.annotations = nil
 	return n
 }
 
+// NewOrderedObjectNode creates a new object node that preserves the order of
+// entries. If a key repeats, the last value wins but the key keeps the place
+// of its first occurrence; the repeats are reported by Duplicates. Use
+// NewObjectNodeWithPolicy to choose another policy.
+func NewOrderedObjectNode(entries []PropertyEntry, pos Position) *ObjectNode {
+	return buildObjectNode(entries, pos, DuplicateKeyLastWins)
+}
+
 // ReleaseObjectNode returns an object node to the pool for reuse.
 // This should be called after the node is no longer needed (e.g., after conversion to interface{}).
 // The node must not be used after calling this function.
@@ -41,6 +74,10 @@ func ReleaseObjectNode(n *ObjectNode) {
 	// Clear properties map to prevent memory leaks
 	// Note: We don't release the map itself to the pool because sizes vary
 	n.properties = nil
+	n.entries = nil
+	n.index = nil
+	n.duplicates = nil
+	n.annotations = nil
 	objectNodePool.Put(n)
 }
 
@@ -49,11 +86,74 @@ func (n *ObjectNode) Type() NodeType {
 	return NodeTypeObject
 }
 
-// Properties returns the property schemas.
+// Properties returns the property schemas. The map has no order; use Keys or
+// PropertyEntries to iterate in source order. The map must not be modified.
 func (n *ObjectNode) Properties() map[string]SchemaNode {
 	return n.properties
 }
 
+// Len returns the number of properties.
+func (n *ObjectNode) Len() int {
+	return len(n.properties)
+}
+
+// Keys returns the distinct property names in order.
+func (n *ObjectNode) Keys() []string {
+	if n.entries != nil {
+		keys := make([]string, 0, len(n.properties))
+		if len(n.entries) == len(n.properties) {
+			for _, entry := range n.entries {
+				keys = append(keys, entry.Key)
+			}
+			return keys
+		}
+
+		// Entries repeat a key only under DuplicateKeyKeepAll
+		seen := make(map[string]bool, len(n.properties))
+		for _, entry := range n.entries {
+			if !seen[entry.Key] {
+				seen[entry.Key] = true
+				keys = append(keys, entry.Key)
+			}
+		}
+		return keys
+	}
+
+	// Sort keys for deterministic output
+	keys := make([]string, 0, len(n.properties))
+	for k := range n.properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// PropertyEntries returns the properties in order. Nodes built from a map
+// report alphabetical order and no key positions. Under DuplicateKeyKeepAll
+// every occurrence of a repeated key is included.
+func (n *ObjectNode) PropertyEntries() []PropertyEntry {
+	if n.entries != nil {
+		return n.entries
+	}
+
+	keys := n.Keys()
+	entries := make([]PropertyEntry, len(keys))
+	for i, k := range keys {
+		entries[i] = PropertyEntry{Key: k, Value: n.properties[k], KeyPosition: ZeroPosition(), SeparatorPosition: ZeroPosition()}
+	}
+	return entries
+}
+
+// KeyPosition returns the source position of a property's key. Properties of
+// nodes built from a map have a zero position.
+func (n *ObjectNode) KeyPosition(name string) (Position, bool) {
+	if i, ok := n.index[name]; ok {
+		return n.entries[i].KeyPosition, true
+	}
+	_, ok := n.properties[name]
+	return ZeroPosition(), ok
+}
+
 // GetProperty returns the schema for a specific property.
 func (n *ObjectNode) GetProperty(name string) (SchemaNode, bool) {
 	node, ok := n.properties[name]
@@ -65,6 +165,28 @@ func (n *ObjectNode) Position() Position {
 	return n.position
 }
 
+// Span returns the source range of the node.
+func (n *ObjectNode) Span() Span {
+	return Span{Start: n.position, End: n.end}
+}
+
+// SetSpan sets the source range of the node, including its position.
+// Parsers call it once the end of the node is known, before sharing the node.
+func (n *ObjectNode) SetSpan(span Span) {
+	n.position = span.Start
+	n.end = span.End
+}
+
+// Annotations returns the comments and metadata attached to the node, or nil.
+func (n *ObjectNode) Annotations() *Annotations {
+	return n.annotations
+}
+
+// SetAnnotations attaches comments and metadata to the node.
+func (n *ObjectNode) SetAnnotations(annotations *Annotations) {
+	n.annotations = annotations
+}
+
 // Accept implements the visitor pattern.
 func (n *ObjectNode) Accept(visitor Visitor) error {
 	return visitor.VisitObject(n)
@@ -76,16 +198,10 @@ func (n *ObjectNode) String() string {
 		return "{}"
 	}
 
-	// Sort keys for deterministic output
-	keys := make([]string, 0, len(n.properties))
-	for k := range n.properties {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-
-	parts := make([]string, len(keys))
-	for i, k := range keys {
-		parts[i] = fmt.Sprintf("%q: %s", k, n.properties[k].String())
+	entries := n.PropertyEntries()
+	parts := make([]string, len(entries))
+	for i, entry := range entries {
+		parts[i] = fmt.Sprintf("%q: %s", entry.Key, entry.Value.String())
 	}
 
 	return fmt.Sprintf("{%s}", strings.Join(parts, ", "))
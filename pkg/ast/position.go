@@ -4,7 +4,7 @@ import "fmt"
 
 // Position represents a location in the source text.
 type Position struct {
-	Offset int // Byte offset (0-indexed)
+	Offset int // Rune offset (0-indexed), as reported by the tokenizer
 	Line   int // Line number (1-indexed)
 	Column int // Column number (1-indexed)
 }
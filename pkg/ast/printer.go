@@ -2,7 +2,6 @@ package ast
 
 import (
 	"fmt"
-	"sort"
 	"strings"
 )
 
@@ -44,17 +43,10 @@ func prettyPrint(node SchemaNode, indent int) string {
 			return fmt.Sprintf("%sObject: {}", prefix)
 		}
 
-		// Sort keys for deterministic output
-		keys := make([]string, 0, len(n.properties))
-		for k := range n.properties {
-			keys = append(keys, k)
-		}
-		sort.Strings(keys)
-
 		lines := []string{fmt.Sprintf("%sObject:", prefix)}
-		for _, k := range keys {
-			childStr := prettyPrint(n.properties[k], indent+1)
-			lines = append(lines, fmt.Sprintf("%s  %q:", prefix, k))
+		for _, entry := range n.PropertyEntries() {
+			childStr := prettyPrint(entry.Value, indent+1)
+			lines = append(lines, fmt.Sprintf("%s  %q:", prefix, entry.Key))
 			lines = append(lines, childStr)
 		}
 		return strings.Join(lines, "\n")
@@ -65,6 +57,18 @@ func prettyPrint(node SchemaNode, indent int) string {
 		lines = append(lines, prettyPrint(n.elementSchema, indent+2))
 		return strings.Join(lines, "\n")
 
+	case *ArrayDataNode:
+		if len(n.elements) == 0 {
+			return fmt.Sprintf("%sArrayData: []", prefix)
+		}
+
+		lines := []string{fmt.Sprintf("%sArrayData:", prefix)}
+		for i, elem := range n.elements {
+			lines = append(lines, fmt.Sprintf("%s  [%d]:", prefix, i))
+			lines = append(lines, prettyPrint(elem, indent+1))
+		}
+		return strings.Join(lines, "\n")
+
 	default:
 		return fmt.Sprintf("%sUnknown node type", prefix)
 	}
@@ -98,12 +102,7 @@ func treePrint(node SchemaNode, prefix string, isLast bool) string {
 	case *ObjectNode:
 		result.WriteString("Object\n")
 
-		// Sort keys
-		keys := make([]string, 0, len(n.properties))
-		for k := range n.properties {
-			keys = append(keys, k)
-		}
-		sort.Strings(keys)
+		entries := n.PropertyEntries()
 
 		childPrefix := prefix
 		if isLast {
@@ -112,23 +111,23 @@ func treePrint(node SchemaNode, prefix string, isLast bool) string {
 			childPrefix += "│   "
 		}
 
-		for i, k := range keys {
+		for i, entry := range entries {
 			result.WriteString(childPrefix)
-			if i == len(keys)-1 {
+			if i == len(entries)-1 {
 				result.WriteString("└── ")
 			} else {
 				result.WriteString("├── ")
 			}
-			result.WriteString(fmt.Sprintf("%q:\n", k))
+			result.WriteString(fmt.Sprintf("%q:\n", entry.Key))
 
 			grandChildPrefix := childPrefix
-			if i == len(keys)-1 {
+			if i == len(entries)-1 {
 				grandChildPrefix += "    "
 			} else {
 				grandChildPrefix += "│   "
 			}
 
-			result.WriteString(treePrint(n.properties[k], grandChildPrefix, true))
+			result.WriteString(treePrint(entry.Value, grandChildPrefix, true))
 		}
 
 	case *ArrayNode:
@@ -143,6 +142,20 @@ func treePrint(node SchemaNode, prefix string, isLast bool) string {
 
 		result.WriteString(treePrint(n.elementSchema, childPrefix, true))
 
+	case *ArrayDataNode:
+		result.WriteString("ArrayData\n")
+
+		childPrefix := prefix
+		if isLast {
+			childPrefix += "    "
+		} else {
+			childPrefix += "│   "
+		}
+
+		for i, elem := range n.elements {
+			result.WriteString(treePrint(elem, childPrefix, i == len(n.elements)-1))
+		}
+
 	default:
 		result.WriteString("Unknown\n")
 	}
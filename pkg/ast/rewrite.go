@@ -0,0 +1,252 @@
+package ast
+
+// ApplyFunc is called by Apply for each node, with a Cursor to inspect and
+// rewrite it.
+type ApplyFunc func(c *Cursor) bool
+
+// Cursor describes a node visited by Apply and lets the callbacks replace or
+// delete it, or insert siblings around it. Its methods panic when the edit
+// does not fit the node's place in the tree, such as deleting the root.
+type Cursor struct {
+	node    SchemaNode
+	path    Path
+	deleted bool
+	before  []PropertyEntry // Siblings to insert before the node
+	after   []PropertyEntry // Siblings to insert after the node
+}
+
+// Node returns the current node, which reflects any Replace.
+func (c *Cursor) Node() SchemaNode {
+	return c.node
+}
+
+// Parent returns the parent of the node as it was before any of its children
+// were rewritten, or nil for the root.
+func (c *Cursor) Parent() SchemaNode {
+	return c.path.Parent()
+}
+
+// Path returns the path from the root to the node. Like the path passed to
+// Traverse callbacks, it is reused and must be copied to keep it.
+func (c *Cursor) Path() Path {
+	return c.path
+}
+
+// Key returns the property key of the node, or "" if its parent is not an
+// ObjectNode.
+func (c *Cursor) Key() string {
+	if _, ok := c.Parent().(*ObjectNode); !ok {
+		return ""
+	}
+	return c.path[len(c.path)-1].Key
+}
+
+// Index returns the index of the node among the children of Parent, or -1
+// for the root. Inserts and deletes of siblings do not shift it.
+func (c *Cursor) Index() int {
+	if len(c.path) == 0 {
+		return -1
+	}
+	return c.path[len(c.path)-1].Index
+}
+
+// Replace replaces the node with node. Replacing in the pre callback makes
+// Apply descend into the new node's children instead.
+func (c *Cursor) Replace(node SchemaNode) {
+	if node == nil {
+		panic("ast: Cursor.Replace called with nil; use Delete")
+	}
+	c.node = node
+}
+
+// Delete removes the node from its parent ObjectNode or ArrayDataNode. The
+// node's children are not visited after a Delete in the pre callback.
+func (c *Cursor) Delete() {
+	c.requireList("Delete")
+	c.deleted = true
+}
+
+// InsertBefore inserts node before the current element of an ArrayDataNode.
+// Inserted nodes are not visited.
+func (c *Cursor) InsertBefore(node SchemaNode) {
+	c.requireParent("InsertBefore", NodeTypeArrayData)
+	c.before = append(c.before, PropertyEntry{Value: node})
+}
+
+// InsertAfter inserts node after the current element of an ArrayDataNode.
+// Inserted nodes are not visited.
+func (c *Cursor) InsertAfter(node SchemaNode) {
+	c.requireParent("InsertAfter", NodeTypeArrayData)
+	c.after = append(c.after, PropertyEntry{Value: node})
+}
+
+// InsertPropertyBefore inserts a property before the current property of an
+// ObjectNode. Inserted nodes are not visited.
+func (c *Cursor) InsertPropertyBefore(key string, node SchemaNode) {
+	c.requireParent("InsertPropertyBefore", NodeTypeObject)
+	c.before = append(c.before, PropertyEntry{Key: key, Value: node})
+}
+
+// InsertPropertyAfter inserts a property after the current property of an
+// ObjectNode. Inserted nodes are not visited.
+func (c *Cursor) InsertPropertyAfter(key string, node SchemaNode) {
+	c.requireParent("InsertPropertyAfter", NodeTypeObject)
+	c.after = append(c.after, PropertyEntry{Key: key, Value: node})
+}
+
+// requireList panics unless the node is a property or an element.
+func (c *Cursor) requireList(method string) {
+	switch c.Parent().(type) {
+	case *ObjectNode, *ArrayDataNode:
+	case nil:
+		panic("ast: Cursor." + method + " called on the root node")
+	default:
+		panic("ast: Cursor." + method + " called on the element schema of an ArrayNode")
+	}
+}
+
+// requireParent panics unless the node's parent has the given type.
+func (c *Cursor) requireParent(method string, parentType NodeType) {
+	if parent := c.Parent(); parent == nil || parent.Type() != parentType {
+		panic("ast: Cursor." + method + " requires a parent of type " + parentType.String())
+	}
+}
+
+// Apply walks root depth-first like Traverse and returns the rewritten tree.
+// For each node it calls pre, then walks the node's children, then calls
+// post. If pre returns false, Apply skips the node's children and does not
+// call post for it. If post returns false, Apply stops and returns the tree
+// rewritten so far. Either callback may be nil.
+//
+// Apply never modifies root or any node of it: parents of changed nodes are
+// rebuilt, keeping their positions, spans and annotations, while unchanged
+// subtrees are shared between root and the result. Do not release pooled
+// nodes of root while the result is in use, or Clone the result first.
+//
+// Rebuilt objects resolve keys added by inserts with DuplicateKeyLastWins, or
+// DuplicateKeyKeepAll if the original kept every occurrence.
+func Apply(root SchemaNode, pre, post ApplyFunc) SchemaNode {
+	if root == nil {
+		return nil
+	}
+	a := &applier{pre: pre, post: post}
+	c := &Cursor{node: root}
+	a.apply(c)
+	return c.node
+}
+
+// applier holds the callbacks of an Apply call.
+type applier struct {
+	pre  ApplyFunc
+	post ApplyFunc
+}
+
+// apply rewrites the node at c and returns false if post aborted.
+func (a *applier) apply(c *Cursor) bool {
+	if a.pre != nil && !a.pre(c) {
+		return true
+	}
+	if c.deleted {
+		return true
+	}
+
+	node, ok := a.applyChildren(c.node, c.path)
+	c.node = node
+	if !ok {
+		return false
+	}
+	return a.post == nil || a.post(c)
+}
+
+// applyChildren rewrites the children of node and returns node, or a rebuilt
+// copy if any child changed, and false if post aborted.
+func (a *applier) applyChildren(node SchemaNode, path Path) (SchemaNode, bool) {
+	switch n := node.(type) {
+	case *ObjectNode:
+		entries, changed, ok := a.applyList(n, n.PropertyEntries(), path)
+		if !changed {
+			return n, ok
+		}
+		return rewrittenObject(n, entries), ok
+
+	case *ArrayNode:
+		c := &Cursor{node: n.elementSchema, path: append(path, PathElement{Parent: n})}
+		ok := a.apply(c)
+		if c.node == n.elementSchema {
+			return n, ok
+		}
+		rebuilt := NewArrayNode(c.node, n.position)
+		rebuilt.end = n.end
+		rebuilt.annotations = n.annotations
+		return rebuilt, ok
+
+	case *ArrayDataNode:
+		entries := make([]PropertyEntry, len(n.elements))
+		for i, elem := range n.elements {
+			entries[i] = PropertyEntry{Value: elem}
+		}
+		entries, changed, ok := a.applyList(n, entries, path)
+		if !changed {
+			return n, ok
+		}
+		elements := make([]SchemaNode, len(entries))
+		for i, entry := range entries {
+			elements[i] = entry.Value
+		}
+		rebuilt := NewArrayDataNode(elements, n.position)
+		rebuilt.end = n.end
+		rebuilt.annotations = n.annotations
+		return rebuilt, ok
+
+	default:
+		return node, true
+	}
+}
+
+// applyList rewrites the children of an ObjectNode or ArrayDataNode, given as
+// entries, and reports whether any changed. After an abort the remaining
+// children are kept as they are.
+func (a *applier) applyList(parent SchemaNode, entries []PropertyEntry, path Path) ([]PropertyEntry, bool, bool) {
+	out := make([]PropertyEntry, 0, len(entries))
+	changed := false
+	for i, entry := range entries {
+		c := &Cursor{node: entry.Value, path: append(path, PathElement{Parent: parent, Key: entry.Key, Index: i})}
+		ok := a.apply(c)
+		if c.deleted || len(c.before) > 0 || len(c.after) > 0 || c.node != entry.Value {
+			changed = true
+		}
+		out = append(out, c.entries(entry)...)
+		if !ok {
+			return append(out, entries[i+1:]...), changed, false
+		}
+	}
+	return out, changed, true
+}
+
+// entries returns what the cursor leaves in place of entry: the inserted
+// siblings around the current node, unless it was deleted.
+func (c *Cursor) entries(entry PropertyEntry) []PropertyEntry {
+	result := append([]PropertyEntry(nil), c.before...)
+	if !c.deleted {
+		entry.Value = c.node
+		result = append(result, entry)
+	}
+	return append(result, c.after...)
+}
+
+// rewrittenObject rebuilds orig with entries.
+func rewrittenObject(orig *ObjectNode, entries []PropertyEntry) *ObjectNode {
+	policy := DuplicateKeyLastWins
+	if len(orig.entries) > len(orig.properties) {
+		policy = DuplicateKeyKeepAll
+	}
+
+	n := buildObjectNode(entries, orig.position, policy)
+	if policy != DuplicateKeyKeepAll && len(orig.duplicates) > 0 {
+		// The source repeats are only visible in the original
+		n.duplicates = append(append([]DuplicateKey(nil), orig.duplicates...), n.duplicates...)
+	}
+	n.end = orig.end
+	n.annotations = orig.annotations
+	return n
+}
@@ -0,0 +1,181 @@
+package ast
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestApply_Replace(t *testing.T) {
+	// {"id": ID, "count": Integer(1, +), "tags": [ID]} with ID an alias of UUID
+	count := NewFunctionNode("Integer", []interface{}{int64(1), "+"}, NewPosition(20, 1, 21))
+	root := NewOrderedObjectNode([]PropertyEntry{
+		{Key: "id", Value: NewTypeNode("ID", NewPosition(7, 1, 8)), KeyPosition: NewPosition(1, 1, 2)},
+		{Key: "count", Value: count},
+		{Key: "tags", Value: NewArrayNode(NewTypeNode("ID", Position{}), Position{})},
+	}, NewPosition(0, 1, 1))
+	root.SetAnnotations(&Annotations{Metadata: map[string]interface{}{"title": "Item"}})
+	before := root.String()
+
+	result := Apply(root, func(c *Cursor) bool {
+		switch n := c.Node().(type) {
+		case *TypeNode:
+			if n.TypeName() == "ID" {
+				c.Replace(NewTypeNode("UUID", n.Position()))
+			}
+		case *FunctionNode:
+			if n.Name() == "Integer" && len(n.Arguments()) == 2 && n.Arguments()[1] == "+" {
+				c.Replace(NewFunctionNode("Integer", []interface{}{n.Arguments()[0], int64(math.MaxInt64)}, n.Position()))
+			}
+		}
+		return true
+	}, nil)
+
+	if want := `{"id": UUID, "count": Integer(1, 9223372036854775807), "tags": [UUID]}`; result.String() != want {
+		t.Errorf("result = %s, want %s", result, want)
+	}
+	if root.String() != before {
+		t.Errorf("original modified: %s", root)
+	}
+
+	obj := result.(*ObjectNode)
+	if obj == root {
+		t.Fatal("Expected a rebuilt root")
+	}
+	if obj.Position() != root.Position() || obj.Annotations() != root.Annotations() {
+		t.Error("Expected the rebuilt root to keep its position and annotations")
+	}
+	if pos, _ := obj.KeyPosition("id"); pos != NewPosition(1, 1, 2) {
+		t.Errorf("KeyPosition(id) = %v", pos)
+	}
+}
+
+func TestApply_SharesUnchangedSubtrees(t *testing.T) {
+	root := walkTestTree()
+	if result := Apply(root, nil, nil); result != SchemaNode(root) {
+		t.Error("Expected an unchanged tree to be returned as is")
+	}
+
+	tags, _ := root.GetProperty("tags")
+	ids, _ := root.GetProperty("ids")
+	result := Apply(root, func(c *Cursor) bool {
+		if c.Key() == "name" {
+			c.Replace(NewLiteralNode("y", Position{}))
+		}
+		return true
+	}, nil).(*ObjectNode)
+
+	if newTags, _ := result.GetProperty("tags"); newTags != tags {
+		t.Error("Expected unchanged properties to be shared")
+	}
+	if newIDs, _ := result.GetProperty("ids"); newIDs != ids {
+		t.Error("Expected unchanged properties to be shared")
+	}
+}
+
+func TestApply_DeleteAndInsert(t *testing.T) {
+	root := walkTestTree()
+	before := root.String()
+
+	result := Apply(root, func(c *Cursor) bool {
+		switch {
+		case c.Key() == "name":
+			c.InsertPropertyBefore("kind", NewLiteralNode("item", Position{}))
+			c.Delete()
+		case c.Key() == "ids":
+			c.InsertPropertyAfter("count", NewLiteralNode(int64(2), Position{}))
+		case c.Parent() != nil && c.Parent().Type() == NodeTypeArrayData:
+			if lit := c.Node().(*LiteralNode); lit.Value() == "a" {
+				c.InsertBefore(NewLiteralNode("first", Position{}))
+				c.InsertAfter(NewLiteralNode("between", Position{}))
+			} else {
+				c.Delete()
+			}
+		}
+		return true
+	}, nil)
+
+	if want := `{"kind": "item", "tags": ["first", "a", "between"], "ids": [UUID], "count": 2}`; result.String() != want {
+		t.Errorf("result = %s, want %s", result, want)
+	}
+	if root.String() != before {
+		t.Errorf("original modified: %s", root)
+	}
+}
+
+func TestApply_PreAndPost(t *testing.T) {
+	var events []string
+	Apply(walkTestTree(), func(c *Cursor) bool {
+		events = append(events, "pre "+c.Path().String())
+		return c.Key() != "tags" // Skip the children and post of tags
+	}, func(c *Cursor) bool {
+		events = append(events, "post "+c.Path().String())
+		return true
+	})
+	want := "pre $, pre $.name, post $.name, pre $.tags, pre $.ids, pre $.ids[], post $.ids[], post $.ids, post $"
+	if got := strings.Join(events, ", "); got != want {
+		t.Errorf("events = %s\nwant %s", got, want)
+	}
+
+	// Returning false from post stops the walk but keeps earlier edits
+	result := Apply(walkTestTree(), nil, func(c *Cursor) bool {
+		if lit, ok := c.Node().(*LiteralNode); ok {
+			c.Replace(NewLiteralNode(strings.ToUpper(lit.Value().(string)), Position{}))
+			return c.Index() != 0 || c.Key() == "name"
+		}
+		return true
+	})
+	if want := `{"name": "X", "tags": ["A", "b"], "ids": [UUID]}`; result.String() != want {
+		t.Errorf("result = %s, want %s", result, want)
+	}
+}
+
+func TestApply_ReplaceRootAndPanics(t *testing.T) {
+	result := Apply(NewTypeNode("ID", Position{}), func(c *Cursor) bool {
+		if c.Index() != -1 || c.Parent() != nil || c.Key() != "" {
+			t.Errorf("root cursor: index %d, parent %v, key %q", c.Index(), c.Parent(), c.Key())
+		}
+		c.Replace(NewTypeNode("UUID", Position{}))
+		return true
+	}, nil)
+	if result.String() != "UUID" {
+		t.Errorf("result = %s, want UUID", result)
+	}
+
+	tests := []struct {
+		name string
+		root SchemaNode
+		edit func(c *Cursor)
+	}{
+		{"delete root", NewTypeNode("UUID", Position{}), func(c *Cursor) { c.Delete() }},
+		{"replace with nil", NewTypeNode("UUID", Position{}), func(c *Cursor) { c.Replace(nil) }},
+		{"delete element schema", NewArrayNode(NewTypeNode("UUID", Position{}), Position{}), func(c *Cursor) {
+			if c.Parent() != nil {
+				c.Delete()
+			}
+		}},
+		{"insert element into object", walkTestTree(), func(c *Cursor) {
+			if c.Key() == "name" {
+				c.InsertAfter(NewLiteralNode(int64(1), Position{}))
+			}
+		}},
+		{"insert property into array", NewArrayDataNode([]SchemaNode{NewLiteralNode(int64(1), Position{})}, Position{}), func(c *Cursor) {
+			if c.Parent() != nil {
+				c.InsertPropertyBefore("x", NewLiteralNode(int64(1), Position{}))
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected a panic")
+				}
+			}()
+			Apply(tt.root, func(c *Cursor) bool {
+				tt.edit(c)
+				return true
+			}, nil)
+		})
+	}
+}
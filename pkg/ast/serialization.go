@@ -1,71 +1,178 @@
 package ast
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // SerializableNode is a helper struct for JSON serialization.
 type SerializableNode struct {
-	Type       string                 `json:"type"`
-	Value      interface{}            `json:"value,omitempty"`
-	TypeName   string                 `json:"typeName,omitempty"`
-	Name       string                 `json:"name,omitempty"`
-	Arguments  []interface{}          `json:"arguments,omitempty"`
-	Properties map[string]interface{} `json:"properties,omitempty"`
-	Element    interface{}            `json:"element,omitempty"`
-	Elements   []interface{}          `json:"elements,omitempty"`
-	Position   *Position              `json:"position,omitempty"`
+	Type       string        `json:"type"`
+	Value      interface{}   `json:"value,omitempty"`
+	ValueType  string        `json:"valueType,omitempty"` // Set for values JSON cannot represent exactly
+	Raw        string        `json:"raw,omitempty"`       // Original lexeme of a literal
+	TypeName   string        `json:"typeName,omitempty"`
+	Name       string        `json:"name,omitempty"`
+	Arguments  []interface{} `json:"arguments,omitempty"`
+	Properties PropertyList  `json:"properties,omitempty"`
+	Element    interface{}   `json:"element,omitempty"`
+	Elements   []interface{} `json:"elements,omitempty"`
+	Position   *Position     `json:"position,omitempty"`
+	End        *Position     `json:"end,omitempty"` // End of the node's span, when known
+
+	// Annotations holds the node's comments and metadata, when it has any
+	Annotations *Annotations `json:"annotations,omitempty"`
+
+	// PropertyPositions parallels Properties when any key position is known
+	PropertyPositions []PropertyPosition `json:"propertyPositions,omitempty"`
+}
+
+// PropertyList holds serialized object properties in order. It marshals as a
+// JSON object whose keys appear in list order, and unmarshals keeping the
+// order of the input, with each value as a json.RawMessage.
+type PropertyList []SerializedProperty
+
+// SerializedProperty is a single entry of a PropertyList.
+type SerializedProperty struct {
+	Key   string
+	Value interface{}
+}
+
+// PropertyPosition holds the serialized key and separator positions of one
+// object property.
+type PropertyPosition struct {
+	Key       Position `json:"key"`
+	Separator Position `json:"separator"`
+}
+
+// MarshalJSON implements json.Marshaler for PropertyList.
+func (l PropertyList) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBufferString("{")
+	for i, prop := range l {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(prop.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(prop.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal property %q: %w", prop.Key, err)
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for PropertyList.
+func (l *PropertyList) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil {
+		return err
+	} else if tok != json.Delim('{') {
+		return fmt.Errorf("properties must be a JSON object, got %v", tok)
+	}
+
+	list := PropertyList{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("unexpected property key %v", tok)
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return fmt.Errorf("failed to decode property %q: %w", key, err)
+		}
+		list = append(list, SerializedProperty{Key: key, Value: value})
+	}
+	*l = list
+	return nil
 }
 
 // MarshalJSON implements json.Marshaler for LiteralNode.
 func (n *LiteralNode) MarshalJSON() ([]byte, error) {
+	value, valueType := encodeLiteralValue(n.value)
 	return json.Marshal(&SerializableNode{
-		Type:     "literal",
-		Value:    n.value,
-		Position: &n.position,
+		Type:        "literal",
+		Value:       value,
+		ValueType:   valueType,
+		Raw:         n.raw,
+		Position:    &n.position,
+		End:         spanEnd(n.end),
+		Annotations: annotationsOrNil(n.annotations),
 	})
 }
 
 // MarshalJSON implements json.Marshaler for TypeNode.
 func (n *TypeNode) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&SerializableNode{
-		Type:     "type",
-		TypeName: n.typeName,
-		Position: &n.position,
+		Type:        "type",
+		TypeName:    n.typeName,
+		Position:    &n.position,
+		End:         spanEnd(n.end),
+		Annotations: annotationsOrNil(n.annotations),
 	})
 }
 
 // MarshalJSON implements json.Marshaler for FunctionNode.
 func (n *FunctionNode) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&SerializableNode{
-		Type:      "function",
-		Name:      n.name,
-		Arguments: n.arguments,
-		Position:  &n.position,
+		Type:        "function",
+		Name:        n.name,
+		Arguments:   n.arguments,
+		Position:    &n.position,
+		End:         spanEnd(n.end),
+		Annotations: annotationsOrNil(n.annotations),
 	})
 }
 
 // MarshalJSON implements json.Marshaler for ObjectNode.
 func (n *ObjectNode) MarshalJSON() ([]byte, error) {
-	props := make(map[string]interface{})
-	for k, v := range n.properties {
-		props[k] = v
+	entries := n.PropertyEntries()
+	props := make(PropertyList, len(entries))
+	positions := make([]PropertyPosition, len(entries))
+	known := false
+	for i, entry := range entries {
+		props[i] = SerializedProperty{Key: entry.Key, Value: entry.Value}
+		positions[i] = PropertyPosition{Key: entry.KeyPosition, Separator: entry.SeparatorPosition}
+		known = known || entry.KeyPosition.IsValid() || entry.SeparatorPosition.IsValid()
+	}
+	if !known {
+		positions = nil // Omit positions for objects built from maps
 	}
 
 	return json.Marshal(&SerializableNode{
-		Type:       "object",
-		Properties: props,
-		Position:   &n.position,
+		Type:              "object",
+		Properties:        props,
+		PropertyPositions: positions,
+		Position:          &n.position,
+		End:               spanEnd(n.end),
+		Annotations:       annotationsOrNil(n.annotations),
 	})
 }
 
 // MarshalJSON implements json.Marshaler for ArrayNode.
 func (n *ArrayNode) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&SerializableNode{
-		Type:     "array",
-		Element:  n.elementSchema,
-		Position: &n.position,
+		Type:        "array",
+		Element:     n.elementSchema,
+		Position:    &n.position,
+		End:         spanEnd(n.end),
+		Annotations: annotationsOrNil(n.annotations),
 	})
 }
 
@@ -77,12 +184,151 @@ func (n *ArrayDataNode) MarshalJSON() ([]byte, error) {
 	}
 
 	return json.Marshal(&SerializableNode{
-		Type:     "arraydata",
-		Elements: elements,
-		Position: &n.position,
+		Type:        "arraydata",
+		Elements:    elements,
+		Position:    &n.position,
+		End:         spanEnd(n.end),
+		Annotations: annotationsOrNil(n.annotations),
 	})
 }
 
+// spanEnd returns the end position to serialize, or nil if it is unknown.
+func spanEnd(end Position) *Position {
+	if !end.IsValid() {
+		return nil
+	}
+	return &end
+}
+
+// Literal value types that JSON cannot represent exactly; such values are
+// serialized as strings tagged with their type.
+const (
+	valueTypeBigInt   = "bigint"
+	valueTypeDecimal  = "decimal"
+	valueTypeBytes    = "bytes"
+	valueTypeTime     = "time"
+	valueTypeInt64    = "int64"
+	valueTypeUint64   = "uint64"
+	valueTypeBigFloat = "bigfloat"
+)
+
+// maxExactFloat is 2^53, the largest magnitude up to which every integer is
+// exactly representable as a float64, and so as a JSON number.
+const maxExactFloat = 1 << 53
+
+// encodeLiteralValue returns the JSON value of a literal and its value type.
+func encodeLiteralValue(value interface{}) (interface{}, string) {
+	switch v := value.(type) {
+	case *big.Int:
+		if v == nil {
+			return nil, ""
+		}
+		return v.String(), valueTypeBigInt
+	case *big.Float:
+		if v == nil {
+			return nil, ""
+		}
+		// Hexadecimal mantissa and binary exponent, which is exact
+		return v.Text('p', 0), valueTypeBigFloat
+	case Decimal:
+		return v.String(), valueTypeDecimal
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v), valueTypeBytes
+	case time.Time:
+		return v.Format(time.RFC3339Nano), valueTypeTime
+	case int:
+		return encodeInt64(int64(v), value)
+	case int64:
+		return encodeInt64(v, value)
+	case uint:
+		return encodeUint64(uint64(v), value)
+	case uint64:
+		return encodeUint64(v, value)
+	default:
+		return value, ""
+	}
+}
+
+// encodeInt64 tags v when a JSON number would round it.
+func encodeInt64(v int64, value interface{}) (interface{}, string) {
+	if v > maxExactFloat || v < -maxExactFloat {
+		return strconv.FormatInt(v, 10), valueTypeInt64
+	}
+	return value, ""
+}
+
+// encodeUint64 tags v when a JSON number would round it.
+func encodeUint64(v uint64, value interface{}) (interface{}, string) {
+	if v > maxExactFloat {
+		return strconv.FormatUint(v, 10), valueTypeUint64
+	}
+	return value, ""
+}
+
+// decodeLiteralValue reverses encodeLiteralValue.
+func decodeLiteralValue(value interface{}, valueType string) (interface{}, error) {
+	if valueType == "" {
+		return value, nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s literal must be a string, got %T", valueType, value)
+	}
+
+	switch valueType {
+	case valueTypeBigInt:
+		n, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid bigint literal %q", s)
+		}
+		return n, nil
+	case valueTypeInt64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid int64 literal: %w", err)
+		}
+		return n, nil
+	case valueTypeUint64:
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid uint64 literal: %w", err)
+		}
+		return n, nil
+	case valueTypeBigFloat:
+		return parseBigFloat(s)
+	case valueTypeDecimal:
+		return ParseDecimal(s)
+	case valueTypeBytes:
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bytes literal: %w", err)
+		}
+		return b, nil
+	case valueTypeTime:
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time literal: %w", err)
+		}
+		return t, nil
+	default:
+		return nil, fmt.Errorf("unknown literal value type: %q", valueType)
+	}
+}
+
+// parseBigFloat parses a *big.Float written by Text('p', 0), with enough
+// precision for every digit of its mantissa.
+func parseBigFloat(s string) (*big.Float, error) {
+	prec := uint(64)
+	if mantissa, _, ok := strings.Cut(s, "p"); ok && uint(len(mantissa))*4 > prec {
+		prec = uint(len(mantissa)) * 4
+	}
+	f, _, err := big.ParseFloat(s, 0, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bigfloat literal: %w", err)
+	}
+	return f, nil
+}
+
 // UnmarshalSchemaNode unmarshals JSON into a SchemaNode.
 func UnmarshalSchemaNode(data []byte) (SchemaNode, error) {
 	var sn SerializableNode
@@ -95,9 +341,34 @@ func UnmarshalSchemaNode(data []byte) (SchemaNode, error) {
 		pos = *sn.Position
 	}
 
+	node, err := decodeSchemaNode(&sn, pos)
+	if err != nil {
+		return nil, err
+	}
+	if sn.End != nil {
+		if setter, ok := node.(interface{ SetSpan(Span) }); ok {
+			setter.SetSpan(NewSpan(pos, *sn.End))
+		}
+	}
+	if sn.Annotations != nil {
+		if setter, ok := node.(interface{ SetAnnotations(*Annotations) }); ok {
+			setter.SetAnnotations(sn.Annotations)
+		}
+	}
+	return node, nil
+}
+
+// decodeSchemaNode builds the node described by sn.
+func decodeSchemaNode(sn *SerializableNode, pos Position) (SchemaNode, error) {
 	switch sn.Type {
 	case "literal":
-		return NewLiteralNode(sn.Value, pos), nil
+		value, err := decodeLiteralValue(sn.Value, sn.ValueType)
+		if err != nil {
+			return nil, err
+		}
+		node := NewLiteralNode(value, pos)
+		node.SetRaw(sn.Raw)
+		return node, nil
 
 	case "type":
 		return NewTypeNode(sn.TypeName, pos), nil
@@ -106,21 +377,26 @@ func UnmarshalSchemaNode(data []byte) (SchemaNode, error) {
 		return NewFunctionNode(sn.Name, sn.Arguments, pos), nil
 
 	case "object":
-		props := make(map[string]SchemaNode)
-		for k, v := range sn.Properties {
-			vBytes, err := json.Marshal(v)
+		entries := make([]PropertyEntry, len(sn.Properties))
+		for i, prop := range sn.Properties {
+			vBytes, err := json.Marshal(prop.Value)
 			if err != nil {
-				return nil, fmt.Errorf("failed to marshal property %q: %w", k, err)
+				return nil, fmt.Errorf("failed to marshal property %q: %w", prop.Key, err)
 			}
 
 			node, err := UnmarshalSchemaNode(vBytes)
 			if err != nil {
-				return nil, fmt.Errorf("failed to unmarshal property %q: %w", k, err)
+				return nil, fmt.Errorf("failed to unmarshal property %q: %w", prop.Key, err)
 			}
 
-			props[k] = node
+			entries[i] = PropertyEntry{Key: prop.Key, Value: node, KeyPosition: ZeroPosition(), SeparatorPosition: ZeroPosition()}
+			if i < len(sn.PropertyPositions) {
+				entries[i].KeyPosition = sn.PropertyPositions[i].Key
+				entries[i].SeparatorPosition = sn.PropertyPositions[i].Separator
+			}
 		}
-		return NewObjectNode(props, pos), nil
+		// Only DuplicateKeyKeepAll serializes a key twice, so keep every entry
+		return buildObjectNode(entries, pos, DuplicateKeyKeepAll), nil
 
 	case "array":
 		elemBytes, err := json.Marshal(sn.Element)
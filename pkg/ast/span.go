@@ -0,0 +1,75 @@
+package ast
+
+import "fmt"
+
+// Span represents a range of source text, from Start up to but not including End.
+// Like Position offsets, the offsets of a span count runes, not bytes.
+type Span struct {
+	Start Position
+	End   Position
+}
+
+// NewSpan creates a new Span from start to end.
+func NewSpan(start, end Position) Span {
+	return Span{Start: start, End: end}
+}
+
+// IsValid returns true if both the start and end positions have been set.
+func (s Span) IsValid() bool {
+	return s.Start.IsValid() && s.End.IsValid()
+}
+
+// Len returns the length of the span in runes, or 0 if it is not valid.
+func (s Span) Len() int {
+	if !s.IsValid() || s.End.Offset < s.Start.Offset {
+		return 0
+	}
+	return s.End.Offset - s.Start.Offset
+}
+
+// Contains returns true if pos lies within the span.
+func (s Span) Contains(pos Position) bool {
+	return s.IsValid() && pos.Offset >= s.Start.Offset && pos.Offset < s.End.Offset
+}
+
+// Text returns the source text covered by the span, or "" if the span is not
+// valid or lies outside source. The rune offsets of the span are converted to
+// byte offsets in source, so text with multi-byte characters slices correctly.
+func (s Span) Text(source string) string {
+	if !s.IsValid() || s.Start.Offset < 0 || s.End.Offset < s.Start.Offset {
+		return ""
+	}
+	start, ok := byteOffset(source, s.Start.Offset)
+	if !ok {
+		return ""
+	}
+	length, ok := byteOffset(source[start:], s.End.Offset-s.Start.Offset)
+	if !ok {
+		return ""
+	}
+	return source[start : start+length]
+}
+
+// byteOffset returns the byte offset of the rune at index runes in source,
+// or false if source has fewer runes.
+func byteOffset(source string, runes int) (int, bool) {
+	n := 0
+	for i := range source {
+		if n == runes {
+			return i, true
+		}
+		n++
+	}
+	return len(source), n == runes
+}
+
+// String returns a string representation of the span.
+func (s Span) String() string {
+	if !s.IsValid() {
+		return s.Start.String()
+	}
+	if s.Start.Line == s.End.Line {
+		return fmt.Sprintf("line %d, columns %d-%d", s.Start.Line, s.Start.Column, s.End.Column)
+	}
+	return fmt.Sprintf("%s to %s", s.Start, s.End)
+}
@@ -2,8 +2,10 @@ package ast
 
 // TypeNode represents type validation (built-in type identifiers like UUID, Email, etc.).
 type TypeNode struct {
-	typeName string // "UUID", "Email", "ISO-8601", etc.
-	position Position
+	typeName    string // "UUID", "Email", "ISO-8601", etc.
+	position    Position
+	end         Position     // End of the source range; see Span
+	annotations *Annotations // Comments and metadata; nil when there are none
 }
 
 // NewTypeNode creates a new type node.
@@ -30,6 +32,28 @@ func (n *TypeNode) Position() Position {
 	return n.position
 }
 
+// Span returns the source range of the node.
+func (n *TypeNode) Span() Span {
+	return Span{Start: n.position, End: n.end}
+}
+
+// SetSpan sets the source range of the node, including its position.
+// Parsers call it once the end of the node is known, before sharing the node.
+func (n *TypeNode) SetSpan(span Span) {
+	n.position = span.Start
+	n.end = span.End
+}
+
+// Annotations returns the comments and metadata attached to the node, or nil.
+func (n *TypeNode) Annotations() *Annotations {
+	return n.annotations
+}
+
+// SetAnnotations attaches comments and metadata to the node.
+func (n *TypeNode) SetAnnotations(annotations *Annotations) {
+	n.annotations = annotations
+}
+
 // Accept implements the visitor pattern.
 func (n *TypeNode) Accept(visitor Visitor) error {
 	return visitor.VisitType(n)
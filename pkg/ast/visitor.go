@@ -11,7 +11,8 @@ type Visitor interface {
 }
 
 // BaseVisitor provides default implementations for the Visitor interface.
-// Embed this in your visitor to only override the methods you need.
+// Embed this in your visitor to only override the methods you need. Its
+// methods do not visit children; pair it with WalkTree to visit every node.
 type BaseVisitor struct{}
 
 // VisitLiteral is the default implementation for visiting literal nodes.
@@ -45,6 +46,8 @@ func (v *BaseVisitor) VisitArrayData(node *ArrayDataNode) error {
 }
 
 // Walk traverses the AST starting from the given node using the provided visitor.
+// It only visits node itself; visitors that need the children recurse on
+// their own. Use WalkTree or Traverse to visit every descendant.
 func Walk(node SchemaNode, visitor Visitor) error {
 	return node.Accept(visitor)
 }
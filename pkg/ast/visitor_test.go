@@ -1,6 +1,8 @@
 package ast
 
 import (
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -153,3 +155,191 @@ func TestWalk_Array(t *testing.T) {
 		t.Error("Walk() did not visit ArrayNode")
 	}
 }
+
+// commentCollector gathers the leading comments of the nodes it visits
+type commentCollector struct {
+	BaseVisitor
+	comments []string
+}
+
+func (v *commentCollector) VisitArrayData(node *ArrayDataNode) error {
+	v.collect(node)
+	for _, elem := range node.Elements() {
+		if err := elem.Accept(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *commentCollector) VisitLiteral(node *LiteralNode) error {
+	v.collect(node)
+	return nil
+}
+
+func (v *commentCollector) collect(node SchemaNode) {
+	if a := node.(Annotated).Annotations(); a != nil {
+		for _, c := range a.Leading {
+			v.comments = append(v.comments, c.Text)
+		}
+	}
+}
+
+func TestWalk_Annotations(t *testing.T) {
+	first := NewLiteralNode(int64(1), Position{})
+	first.SetAnnotations(&Annotations{Leading: []Comment{{Text: "// first"}}})
+	second := NewLiteralNode(int64(2), Position{})
+	root := NewArrayDataNode([]SchemaNode{first, second}, Position{})
+	root.SetAnnotations(&Annotations{Leading: []Comment{{Text: "/* list */"}}})
+
+	visitor := &commentCollector{}
+	if err := Walk(root, visitor); err != nil {
+		t.Fatalf("Walk() error = %v, want nil", err)
+	}
+	if got := strings.Join(visitor.comments, ", "); got != "/* list */, // first" {
+		t.Errorf("comments = %q, want %q", got, "/* list */, // first")
+	}
+}
+
+// walkTestTree builds {"name": "x", "tags": ["a", "b"], "ids": [UUID]}
+func walkTestTree() *ObjectNode {
+	return NewOrderedObjectNode([]PropertyEntry{
+		{Key: "name", Value: NewLiteralNode("x", Position{})},
+		{Key: "tags", Value: NewArrayDataNode([]SchemaNode{
+			NewLiteralNode("a", Position{}),
+			NewLiteralNode("b", Position{}),
+		}, Position{})},
+		{Key: "ids", Value: NewArrayNode(NewTypeNode("UUID", Position{}), Position{})},
+	}, Position{})
+}
+
+func TestTraverse_Order(t *testing.T) {
+	var events []string
+	record := func(prefix string) TraverseFunc {
+		return func(node SchemaNode, path Path) WalkAction {
+			events = append(events, prefix+" "+path.String()+" "+node.Type().String())
+			return WalkContinue
+		}
+	}
+
+	if !Traverse(walkTestTree(), record("enter"), record("leave")) {
+		t.Fatal("Traverse() = false, want true")
+	}
+
+	expected := []string{
+		"enter $ Object",
+		"enter $.name Literal",
+		"leave $.name Literal",
+		"enter $.tags ArrayData",
+		"enter $.tags[0] Literal",
+		"leave $.tags[0] Literal",
+		"enter $.tags[1] Literal",
+		"leave $.tags[1] Literal",
+		"leave $.tags ArrayData",
+		"enter $.ids Array",
+		"enter $.ids[] Type",
+		"leave $.ids[] Type",
+		"leave $.ids Array",
+		"leave $ Object",
+	}
+	if got, want := strings.Join(events, "\n"), strings.Join(expected, "\n"); got != want {
+		t.Errorf("events:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTraverse_SkipAndStop(t *testing.T) {
+	var entered []string
+	Traverse(walkTestTree(), func(node SchemaNode, path Path) WalkAction {
+		entered = append(entered, path.String())
+		if node.Type() == NodeTypeArrayData {
+			return WalkSkipChildren
+		}
+		return WalkContinue
+	}, nil)
+	if got := strings.Join(entered, " "); got != "$ $.name $.tags $.ids $.ids[]" {
+		t.Errorf("entered = %s", got)
+	}
+
+	var left []string
+	completed := Traverse(walkTestTree(), func(node SchemaNode, path Path) WalkAction {
+		if path.String() == "$.tags[1]" {
+			return WalkStop
+		}
+		return WalkContinue
+	}, func(node SchemaNode, path Path) WalkAction {
+		left = append(left, path.String())
+		return WalkContinue
+	})
+	if completed {
+		t.Error("Traverse() = true, want false after WalkStop")
+	}
+	if got := strings.Join(left, " "); got != "$.name $.tags[0]" {
+		t.Errorf("left = %s", got)
+	}
+
+	// Stopping from leave ends the traversal too
+	count := 0
+	Traverse(walkTestTree(), nil, func(node SchemaNode, path Path) WalkAction {
+		count++
+		return WalkStop
+	})
+	if count != 1 {
+		t.Errorf("leave called %d times, want 1", count)
+	}
+}
+
+func TestTraverse_Path(t *testing.T) {
+	root := walkTestTree()
+	var tagsPath Path
+	Traverse(root, func(node SchemaNode, path Path) WalkAction {
+		if lit, ok := node.(*LiteralNode); ok && lit.Value() == "b" {
+			tagsPath = append(Path(nil), path...)
+		}
+		return WalkContinue
+	}, nil)
+
+	if len(tagsPath) != 2 || tagsPath[0].Parent != root || tagsPath[0].Key != "tags" || tagsPath[0].Index != 1 || tagsPath[1].Index != 1 {
+		t.Fatalf("path = %+v", tagsPath)
+	}
+	if _, ok := tagsPath.Parent().(*ArrayDataNode); !ok {
+		t.Errorf("Parent() = %T, want *ArrayDataNode", tagsPath.Parent())
+	}
+	if (Path{}).Parent() != nil {
+		t.Error("Expected the root to have no parent")
+	}
+
+	odd := Path{{Parent: root, Key: "first name"}, {Parent: root, Key: "_id2"}, {Parent: root, Key: ""}}
+	if got := odd.String(); got != `$["first name"]._id2[""]` {
+		t.Errorf("String() = %s", got)
+	}
+}
+
+func TestWalkTree(t *testing.T) {
+	visitor := &TestVisitor{}
+	if err := WalkTree(walkTestTree(), visitor); err != nil {
+		t.Fatalf("WalkTree() error = %v", err)
+	}
+	if !visitor.visitedObject || !visitor.visitedLiteral || !visitor.visitedArray || !visitor.visitedType {
+		t.Errorf("WalkTree() did not visit every node type: %+v", visitor)
+	}
+
+	failing := &failingVisitor{}
+	err := WalkTree(walkTestTree(), failing)
+	if err == nil || err.Error() != "literal rejected" {
+		t.Errorf("WalkTree() error = %v, want literal rejected", err)
+	}
+	if failing.literals != 1 {
+		t.Errorf("visited %d literals, want 1", failing.literals)
+	}
+}
+
+// failingVisitor fails on the first literal
+type failingVisitor struct {
+	BaseVisitor
+	literals int
+}
+
+func (v *failingVisitor) VisitLiteral(node *LiteralNode) error {
+	v.literals++
+	return errors.New("literal rejected")
+}
@@ -0,0 +1,159 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WalkAction tells Traverse how to continue after a callback.
+type WalkAction int
+
+const (
+	// WalkContinue visits the node's children, then its following siblings
+	WalkContinue WalkAction = iota
+
+	// WalkSkipChildren skips the node's children; the leave callback is
+	// still called for the node. Returned from a leave callback it has the
+	// same effect as WalkContinue.
+	WalkSkipChildren
+
+	// WalkStop ends the traversal without calling any further callbacks
+	WalkStop
+)
+
+// TraverseFunc is called by Traverse for each node, with the path from the
+// root to the node.
+type TraverseFunc func(node SchemaNode, path Path) WalkAction
+
+// PathElement is one step from a node to one of its children.
+type PathElement struct {
+	Parent SchemaNode // The node whose child this step leads to
+	Key    string     // Property key, when Parent is an ObjectNode
+	Index  int        // Index among Parent's children; 0 for an ArrayNode's element schema
+}
+
+// Path leads from the root of a traversal to a node. The root has an empty
+// path.
+type Path []PathElement
+
+// Parent returns the parent of the node the path leads to, or nil for the
+// root.
+func (p Path) Parent() SchemaNode {
+	if len(p) == 0 {
+		return nil
+	}
+	return p[len(p)-1].Parent
+}
+
+// String returns the path in the JSONPath-like notation used in validation
+// errors: "$.users[0].name". An ArrayNode's element schema is written "[]".
+func (p Path) String() string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, elem := range p {
+		switch elem.Parent.(type) {
+		case *ObjectNode:
+			if isPathIdentifier(elem.Key) {
+				b.WriteString("." + elem.Key)
+			} else {
+				fmt.Fprintf(&b, "[%q]", elem.Key)
+			}
+		case *ArrayNode:
+			b.WriteString("[]")
+		default:
+			fmt.Fprintf(&b, "[%d]", elem.Index)
+		}
+	}
+	return b.String()
+}
+
+// isPathIdentifier returns true if key can be written after a '.' in a path.
+func isPathIdentifier(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		isLetter := r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		if !isLetter && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// Traverse visits root and all of its descendants depth-first, calling enter
+// before a node's children (pre-order) and leave after them (post-order).
+// Object properties are visited in order, including every occurrence of a
+// key kept by DuplicateKeyKeepAll. Either callback may be nil. The path
+// passed to the callbacks is reused; copy it to keep it after the callback
+// returns.
+//
+// Traverse returns false if a callback stopped the traversal with WalkStop.
+func Traverse(root SchemaNode, enter, leave TraverseFunc) bool {
+	t := &traversal{enter: enter, leave: leave}
+	return t.walk(root, nil)
+}
+
+// WalkTree calls the visitor for root and each of its descendants in
+// pre-order, stopping at the first error. Unlike Walk, the visitor does not
+// need to recurse into children itself, and must not, or descendants are
+// visited twice.
+func WalkTree(root SchemaNode, visitor Visitor) error {
+	var err error
+	Traverse(root, func(node SchemaNode, _ Path) WalkAction {
+		if err = node.Accept(visitor); err != nil {
+			return WalkStop
+		}
+		return WalkContinue
+	}, nil)
+	return err
+}
+
+// traversal holds the callbacks of a Traverse call.
+type traversal struct {
+	enter TraverseFunc
+	leave TraverseFunc
+}
+
+// walk visits node and its descendants and returns false if stopped.
+func (t *traversal) walk(node SchemaNode, path Path) bool {
+	if node == nil {
+		return true
+	}
+
+	action := WalkContinue
+	if t.enter != nil {
+		action = t.enter(node, path)
+	}
+	if action == WalkStop {
+		return false
+	}
+	if action != WalkSkipChildren && !t.walkChildren(node, path) {
+		return false
+	}
+
+	return t.leave == nil || t.leave(node, path) != WalkStop
+}
+
+// walkChildren visits the children of node in order.
+func (t *traversal) walkChildren(node SchemaNode, path Path) bool {
+	switch n := node.(type) {
+	case *ObjectNode:
+		for i, entry := range n.PropertyEntries() {
+			if !t.walk(entry.Value, append(path, PathElement{Parent: n, Key: entry.Key, Index: i})) {
+				return false
+			}
+		}
+
+	case *ArrayNode:
+		return t.walk(n.elementSchema, append(path, PathElement{Parent: n}))
+
+	case *ArrayDataNode:
+		for i, elem := range n.elements {
+			if !t.walk(elem, append(path, PathElement{Parent: n, Index: i})) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+// Package golden provides golden-file test helpers for parsers built on
+// shape-core.
+//
+// A golden test keeps each input next to its expected output in a testdata
+// directory:
+//
+//	testdata/tokens/strings.input
+//	testdata/tokens/strings.golden
+//
+// RunTokens lexes every input with a tokenizer.Definition and compares the
+// token stream with the golden file; RunAST parses every input with a
+// user-supplied function and compares the ast.PrettyPrint rendering. Mismatches
+// are reported as a unified diff (tokenizer.UnifiedDiff).
+//
+// Running the tests with the -golden.update flag, or with GOLDEN_UPDATE=1 in
+// the environment, rewrites the golden files with the actual output instead
+// of comparing:
+//
+//	go test ./... -run TestGolden -golden.update
+//	GOLDEN_UPDATE=1 go test ./...
+//
+// The environment variable also works with go test ./... across packages
+// that do not import golden, where an unknown flag would fail the run.
+//
+// Basic usage:
+//
+//	func TestGoldenTokens(t *testing.T) {
+//	    golden.RunTokens(t, "testdata/tokens", myDefinition)
+//	}
+//
+//	func TestGoldenAST(t *testing.T) {
+//	    golden.RunAST(t, "testdata/ast", func(input string) (ast.SchemaNode, error) {
+//	        return myparser.Parse(input)
+//	    })
+//	}
+//
+// The -golden.update flag is registered on flag.CommandLine when the package
+// is imported; test packages can still define their own -update flag.
+package golden
@@ -0,0 +1,158 @@
+package golden
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/shapestone/shape-core/pkg/ast"
+	"github.com/shapestone/shape-core/pkg/grammar"
+	"github.com/shapestone/shape-core/pkg/tokenizer"
+)
+
+const (
+	// InputExt is the extension of golden test inputs
+	InputExt = ".input"
+	// GoldenExt is the extension of expected outputs
+	GoldenExt = ".golden"
+)
+
+// UpdateEnv is the environment variable that enables updating golden files
+// when set to a true value such as "1", as an alternative to the flag.
+const UpdateEnv = "GOLDEN_UPDATE"
+
+// The flag is namespaced so that importing this package does not clash with
+// an -update flag defined by the test package itself.
+var update = flag.Bool("golden.update", false, "rewrite golden files with actual output")
+
+// Update returns true if the tests were run with -golden.update or with
+// GOLDEN_UPDATE set to a true value.
+func Update() bool {
+	if *update {
+		return true
+	}
+	enabled, err := strconv.ParseBool(os.Getenv(UpdateEnv))
+	return err == nil && enabled
+}
+
+// Case is one input/golden pair loaded from a testdata directory.
+type Case struct {
+	Name       string // File name without extension, used as the subtest name
+	InputPath  string
+	GoldenPath string
+	Input      string
+}
+
+// Cases loads every *.input file in dir, in name order. The golden path of
+// each case is the input path with the extension replaced by .golden; the
+// golden file itself may not exist yet when updating.
+func Cases(t testing.TB, dir string) []Case {
+	t.Helper()
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+InputExt))
+	if err != nil {
+		t.Fatalf("golden: %v", err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("golden: no %s files in %s", InputExt, dir)
+	}
+	sort.Strings(paths)
+
+	cases := make([]Case, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("golden: %v", err)
+		}
+		name := strings.TrimSuffix(filepath.Base(path), InputExt)
+		cases = append(cases, Case{
+			Name:       name,
+			InputPath:  path,
+			GoldenPath: strings.TrimSuffix(path, InputExt) + GoldenExt,
+			Input:      string(data),
+		})
+	}
+	return cases
+}
+
+// Assert compares actual with the contents of goldenPath and reports a unified
+// diff on mismatch. When Update is true it writes actual to goldenPath instead.
+//
+// Golden files are stored with a trailing newline, which is ignored when comparing.
+func Assert(t testing.TB, goldenPath string, actual string) {
+	t.Helper()
+
+	if Update() {
+		if err := os.WriteFile(goldenPath, []byte(actual+"\n"), 0o644); err != nil {
+			t.Fatalf("golden: %v", err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("golden: %v (run with -golden.update to create it)", err)
+	}
+	expected := strings.TrimSuffix(string(data), "\n")
+
+	options := tokenizer.DefaultDiffOptions()
+	options.ExpectedName = goldenPath
+	options.ActualName = "actual"
+	if diff, ok := tokenizer.UnifiedDiffWithOptions(expected, actual, options); !ok {
+		t.Errorf("golden mismatch (run with -golden.update to accept):\n%s", diff)
+	}
+}
+
+// RunTokens lexes each input in dir with a tokenizer from definition and
+// compares the token stream, one token per line, with its golden file.
+func RunTokens(t *testing.T, dir string, definition *tokenizer.Definition) {
+	t.Helper()
+
+	for _, c := range Cases(t, dir) {
+		t.Run(c.Name, func(t *testing.T) {
+			tok := definition.NewTokenizer(c.Input)
+			Assert(t, c.GoldenPath, tok.TokenizeToString("\n"))
+		})
+	}
+}
+
+// RunAST parses each input in dir with parse and compares the ast.PrettyPrint
+// rendering with its golden file. A parse error is rendered as "error: <message>",
+// so golden files can also pin down error cases.
+func RunAST(t *testing.T, dir string, parse func(input string) (ast.SchemaNode, error)) {
+	t.Helper()
+
+	for _, c := range Cases(t, dir) {
+		t.Run(c.Name, func(t *testing.T) {
+			Assert(t, c.GoldenPath, RenderAST(parse(c.Input)))
+		})
+	}
+}
+
+// RenderAST returns the golden representation of a parse result.
+func RenderAST(node ast.SchemaNode, err error) string {
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	if node == nil {
+		return "<nil>"
+	}
+	return ast.PrettyPrint(node)
+}
+
+// AssertAST compares two ASTs with grammar.ASTEqual and reports the first
+// difference plus a unified diff of their ast.PrettyPrint renderings.
+// Positions are ignored.
+func AssertAST(t testing.TB, expected, actual ast.SchemaNode) {
+	t.Helper()
+
+	if grammar.ASTEqual(expected, actual) {
+		return
+	}
+	diff, _ := tokenizer.UnifiedDiff(RenderAST(expected, nil), RenderAST(actual, nil))
+	t.Errorf("AST mismatch: %s\n%s", grammar.ASTDiff(expected, actual), diff)
+}
@@ -0,0 +1,141 @@
+package golden
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shapestone/shape-core/pkg/ast"
+	"github.com/shapestone/shape-core/pkg/tokenizer"
+)
+
+// recorder captures failures instead of failing the enclosing test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func parseLines(input string) (ast.SchemaNode, error) {
+	if input == "" {
+		return nil, errors.New("empty input")
+	}
+	var elements []ast.SchemaNode
+	for i, line := range strings.Split(input, "\n") {
+		elements = append(elements, ast.NewLiteralNode(line, ast.NewPosition(0, i+1, 1)))
+	}
+	return ast.NewArrayDataNode(elements, ast.NewPosition(0, 1, 1)), nil
+}
+
+func TestRunTokens(t *testing.T) {
+	definition := tokenizer.NewDefinition(
+		tokenizer.StringMatcherFunc("Text", "true"),
+		tokenizer.CharMatcherFunc("Equals", '='),
+	)
+	RunTokens(t, "testdata/tokens", definition)
+}
+
+func TestRunAST(t *testing.T) {
+	RunAST(t, "testdata/ast", parseLines)
+}
+
+func TestCases(t *testing.T) {
+	cases := Cases(t, "testdata/ast")
+	if len(cases) != 2 || cases[0].Name != "empty" || cases[1].Name != "list" {
+		t.Fatalf("Expected cases [empty list], got %+v", cases)
+	}
+	if cases[1].Input != "alpha\nbeta" {
+		t.Errorf("Input = %q, want %q", cases[1].Input, "alpha\nbeta")
+	}
+	if want := filepath.Join("testdata", "ast", "list.golden"); cases[1].GoldenPath != want {
+		t.Errorf("GoldenPath = %q, want %q", cases[1].GoldenPath, want)
+	}
+}
+
+func TestAssertReportsUnifiedDiff(t *testing.T) {
+	t.Setenv(UpdateEnv, "")
+	path := filepath.Join(t.TempDir(), "case.golden")
+	if err := os.WriteFile(path, []byte("a\nb\nc\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &recorder{}
+	Assert(r, path, "a\nx\nc")
+	if len(r.errors) != 1 {
+		t.Fatalf("Expected one failure, got %v", r.errors)
+	}
+	for _, want := range []string{"-b\n", "+x\n", " a\n", "-golden.update"} {
+		if !strings.Contains(r.errors[0], want) {
+			t.Errorf("Failure missing %q:\n%s", want, r.errors[0])
+		}
+	}
+
+	r = &recorder{}
+	Assert(r, path, "a\nb\nc")
+	if len(r.errors) != 0 {
+		t.Errorf("Expected no failure, got %v", r.errors)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	t.Setenv(UpdateEnv, "")
+	if Update() {
+		t.Fatal("Expected Update to be false without the flag or environment variable")
+	}
+	for value, want := range map[string]bool{"1": true, "true": true, "0": false, "yes": false} {
+		t.Setenv(UpdateEnv, value)
+		if Update() != want {
+			t.Errorf("Update() with %s=%q = %v, want %v", UpdateEnv, value, !want, want)
+		}
+	}
+	if flag.Lookup("golden.update") == nil || flag.Lookup("update") != nil {
+		t.Error("Expected only the namespaced -golden.update flag to be registered")
+	}
+}
+
+func TestAssertUpdate(t *testing.T) {
+	defer func(saved bool) { *update = saved }(*update)
+	*update = true
+
+	path := filepath.Join(t.TempDir(), "new.golden")
+	Assert(t, path, "fresh output")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "fresh output\n" {
+		t.Errorf("Golden file = %q, want %q", data, "fresh output\n")
+	}
+}
+
+func TestAssertAST(t *testing.T) {
+	expected, _ := parseLines("alpha\nbeta")
+	same, _ := parseLines("alpha\nbeta")
+	different, _ := parseLines("alpha\ngamma")
+
+	r := &recorder{}
+	AssertAST(r, expected, same)
+	if len(r.errors) != 0 {
+		t.Errorf("Expected equal ASTs, got %v", r.errors)
+	}
+
+	AssertAST(r, expected, different)
+	if len(r.errors) != 1 {
+		t.Fatalf("Expected one failure, got %v", r.errors)
+	}
+	for _, want := range []string{"in array element 1", `-  Literal: "beta"`, `+  Literal: "gamma"`} {
+		if !strings.Contains(r.errors[0], want) {
+			t.Errorf("Failure missing %q:\n%s", want, r.errors[0])
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package grammar
 
 import (
+	"bytes"
 	"fmt"
+	"math/big"
+	"time"
 
 	"github.com/shapestone/shape-core/pkg/ast"
 )
@@ -9,7 +12,10 @@ import (
 // ASTEqual performs deep comparison of two AST nodes for structural equality.
 //
 // Returns true if the nodes have the same type, structure, and values.
-// Position information is ignored in the comparison.
+// Position information, annotations (comments, metadata) and the raw lexemes
+// of literals are ignored in the comparison. Object properties are compared
+// by key regardless of order; every occurrence of a key kept by
+// ast.DuplicateKeyKeepAll counts, in source order.
 //
 // This is useful for dual parser verification where a reference parser's
 // output is compared against a production parser's output.
@@ -46,6 +52,8 @@ func ASTEqual(a, b ast.SchemaNode) bool {
 		return objectEqual(aNode, b.(*ast.ObjectNode))
 	case *ast.ArrayNode:
 		return arrayEqual(aNode, b.(*ast.ArrayNode))
+	case *ast.ArrayDataNode:
+		return arrayDataEqual(aNode, b.(*ast.ArrayDataNode))
 	default:
 		return false
 	}
@@ -56,16 +64,7 @@ func literalEqual(a, b *ast.LiteralNode) bool {
 	aVal := a.Value()
 	bVal := b.Value()
 
-	// Handle nil values
-	if aVal == nil && bVal == nil {
-		return true
-	}
-	if aVal == nil || bVal == nil {
-		return false
-	}
-
-	// Compare values using type assertion
-	return aVal == bVal
+	return interfaceEqual(aVal, bVal)
 }
 
 func typeEqual(a, b *ast.TypeNode) bool {
@@ -104,14 +103,18 @@ func objectEqual(a, b *ast.ObjectNode) bool {
 		return false
 	}
 
-	// Compare each property (recursively)
-	for key, aValue := range aProps {
-		bValue, exists := bProps[key]
-		if !exists {
+	// Compare each property (recursively), including every occurrence of a
+	// key kept by DuplicateKeyKeepAll, in order
+	for key := range aProps {
+		aValues := a.GetAll(key)
+		bValues := b.GetAll(key)
+		if len(aValues) != len(bValues) {
 			return false
 		}
-		if !ASTEqual(aValue, bValue) {
-			return false
+		for i := range aValues {
+			if !ASTEqual(aValues[i], bValues[i]) {
+				return false
+			}
 		}
 	}
 
@@ -123,7 +126,23 @@ func arrayEqual(a, b *ast.ArrayNode) bool {
 	return ASTEqual(a.ElementSchema(), b.ElementSchema())
 }
 
-// interfaceEqual compares two interface{} values (for function arguments)
+func arrayDataEqual(a, b *ast.ArrayDataNode) bool {
+	// Compare element counts, then each element in order (recursively)
+	if a.Len() != b.Len() {
+		return false
+	}
+	for i, aElem := range a.Elements() {
+		if !ASTEqual(aElem, b.Get(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// interfaceEqual compares two interface{} values (for literals and function
+// arguments). Big integers, big floats and decimals compare by value, so 1.50
+// equals 1.5 whatever the precision,
+// and times compare as instants.
 func interfaceEqual(a, b interface{}) bool {
 	if a == nil && b == nil {
 		return true
@@ -131,6 +150,24 @@ func interfaceEqual(a, b interface{}) bool {
 	if a == nil || b == nil {
 		return false
 	}
+
+	switch av := a.(type) {
+	case *big.Int:
+		bv, ok := b.(*big.Int)
+		return ok && av != nil && bv != nil && av.Cmp(bv) == 0
+	case *big.Float:
+		bv, ok := b.(*big.Float)
+		return ok && av != nil && bv != nil && av.Cmp(bv) == 0
+	case ast.Decimal:
+		bv, ok := b.(ast.Decimal)
+		return ok && av.Cmp(bv) == 0
+	case []byte:
+		bv, ok := b.([]byte)
+		return ok && bytes.Equal(av, bv)
+	case time.Time:
+		bv, ok := b.(time.Time)
+		return ok && av.Equal(bv)
+	}
 	return a == b
 }
 
@@ -196,13 +233,23 @@ func ASTDiff(a, b ast.SchemaNode) string {
 		if len(aProps) != len(bProps) {
 			return "object property counts differ"
 		}
-		for key, aValue := range aProps {
-			bValue, exists := bProps[key]
-			if !exists {
+		for _, key := range aNode.Keys() {
+			if _, exists := bProps[key]; !exists {
 				return "property missing in second object: " + key
 			}
-			if diff := ASTDiff(aValue, bValue); diff != "" {
-				return "in property " + key + ": " + diff
+			aValues := aNode.GetAll(key)
+			bValues := bNode.GetAll(key)
+			if len(aValues) != len(bValues) {
+				return fmt.Sprintf("property %s occurrence counts differ: %d vs %d", key, len(aValues), len(bValues))
+			}
+			for i := range aValues {
+				diff := ASTDiff(aValues[i], bValues[i])
+				if diff != "" && len(aValues) > 1 {
+					return fmt.Sprintf("in property %s (occurrence %d): %s", key, i+1, diff)
+				}
+				if diff != "" {
+					return "in property " + key + ": " + diff
+				}
 			}
 		}
 
@@ -211,6 +258,17 @@ func ASTDiff(a, b ast.SchemaNode) string {
 		if diff := ASTDiff(aNode.ElementSchema(), bNode.ElementSchema()); diff != "" {
 			return "in array element schema: " + diff
 		}
+
+	case *ast.ArrayDataNode:
+		bNode := b.(*ast.ArrayDataNode)
+		if aNode.Len() != bNode.Len() {
+			return "array element counts differ"
+		}
+		for i, aElem := range aNode.Elements() {
+			if diff := ASTDiff(aElem, bNode.Get(i)); diff != "" {
+				return fmt.Sprintf("in array element %d: %s", i, diff)
+			}
+		}
 	}
 
 	return ""
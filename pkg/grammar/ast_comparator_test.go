@@ -1,7 +1,9 @@
 package grammar
 
 import (
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/shapestone/shape-core/pkg/ast"
 )
@@ -70,6 +72,50 @@ func TestASTEqual_LiteralNodes(t *testing.T) {
 	}
 }
 
+func TestASTEqual_RichLiterals(t *testing.T) {
+	big1, _ := new(big.Int).SetString("12345678901234567890", 10)
+	big2, _ := new(big.Int).SetString("12345678901234567890", 10)
+	oneFifty, _ := ast.ParseDecimal("1.50")
+	onePointFive, _ := ast.ParseDecimal("1.5")
+	utc := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	local := utc.In(time.FixedZone("CET", 3600))
+
+	tests := []struct {
+		name     string
+		a, b     interface{}
+		expected bool
+	}{
+		{"equal big ints", big1, big2, true},
+		{"different big ints", big1, big.NewInt(1), false},
+		{"big int vs int64", big.NewInt(1), int64(1), false},
+		{"decimals compare by value", oneFifty, onePointFive, true},
+		{"big floats compare by value", big.NewFloat(1.5).SetPrec(200), big.NewFloat(1.5), true},
+		{"different big floats", big.NewFloat(1.5), big.NewFloat(2.5), false},
+		{"equal bytes", []byte("abc"), []byte("abc"), true},
+		{"different bytes", []byte("abc"), []byte("abd"), false},
+		{"bytes vs string", []byte("abc"), "abc", false},
+		{"same instant in different zones", utc, local, true},
+		{"different instants", utc, utc.Add(time.Second), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := ast.NewLiteralNode(tt.a, ast.Position{})
+			b := ast.NewLiteralNode(tt.b, ast.Position{})
+			if result := ASTEqual(a, b); result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+
+	// The raw lexeme does not take part in the comparison
+	a := ast.NewLiteralNode(int64(31), ast.Position{})
+	a.SetRaw("0x1F")
+	if !ASTEqual(a, ast.NewLiteralNode(int64(31), ast.Position{})) {
+		t.Error("expected raw lexemes to be ignored")
+	}
+}
+
 func TestASTEqual_TypeNodes(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -406,6 +452,32 @@ func TestASTDiff_ArrayNodes(t *testing.T) {
 	}
 }
 
+func TestASTDiff_ArrayDataNodes(t *testing.T) {
+	a := ast.NewArrayDataNode([]ast.SchemaNode{
+		ast.NewLiteralNode("x", ast.Position{}),
+		ast.NewLiteralNode(int64(1), ast.Position{}),
+	}, ast.Position{})
+	b := ast.NewArrayDataNode([]ast.SchemaNode{
+		ast.NewLiteralNode("x", ast.Position{}),
+		ast.NewLiteralNode(int64(2), ast.Position{}),
+	}, ast.Position{})
+
+	if !ASTEqual(a, a) {
+		t.Error("expected array data to equal itself")
+	}
+	if ASTEqual(a, b) {
+		t.Error("expected array data with different elements to differ")
+	}
+	if diff := ASTDiff(a, b); !stringContains(diff, "in array element 1") {
+		t.Errorf("expected 'in array element 1', got: %s", diff)
+	}
+
+	shorter := ast.NewArrayDataNode(a.Elements()[:1], ast.Position{})
+	if diff := ASTDiff(a, shorter); !stringContains(diff, "array element counts differ") {
+		t.Errorf("expected 'array element counts differ', got: %s", diff)
+	}
+}
+
 func TestASTDiff_NestedStructures(t *testing.T) {
 	// Test deeply nested object comparison
 	a := ast.NewObjectNode(map[string]ast.SchemaNode{
@@ -428,3 +500,43 @@ func TestASTDiff_NestedStructures(t *testing.T) {
 		t.Errorf("expected diff to mention 'property user', got: %s", diff)
 	}
 }
+
+func TestASTEqual_KeepAllDuplicates(t *testing.T) {
+	object := func(policy ast.DuplicateKeyPolicy, values ...int64) *ast.ObjectNode {
+		entries := make([]ast.PropertyEntry, len(values))
+		for i, v := range values {
+			entries[i] = ast.PropertyEntry{Key: "a", Value: ast.NewLiteralNode(v, ast.Position{})}
+		}
+		node, err := ast.NewObjectNodeWithPolicy(entries, ast.Position{}, policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return node
+	}
+
+	tests := []struct {
+		name         string
+		a, b         *ast.ObjectNode
+		expectedText string
+	}{
+		{"identical", object(ast.DuplicateKeyKeepAll, 1, 2), object(ast.DuplicateKeyKeepAll, 1, 2), ""},
+		{"earlier occurrence differs", object(ast.DuplicateKeyKeepAll, 1, 2), object(ast.DuplicateKeyKeepAll, 3, 2), "in property a (occurrence 1)"},
+		{"occurrence counts differ", object(ast.DuplicateKeyKeepAll, 1, 2), object(ast.DuplicateKeyLastWins, 1, 2), "occurrence counts differ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			equal := ASTEqual(tt.a, tt.b)
+			diff := ASTDiff(tt.a, tt.b)
+			if equal != (tt.expectedText == "") || equal != (diff == "") {
+				t.Errorf("ASTEqual() = %v but ASTDiff() = %q", equal, diff)
+			}
+			if !stringContains(diff, tt.expectedText) {
+				t.Errorf("expected diff to contain '%s', got: %s", tt.expectedText, diff)
+			}
+			if equal != (ast.Hash(tt.a) == ast.Hash(tt.b)) {
+				t.Errorf("expected Hash() to agree with ASTEqual() = %v", equal)
+			}
+		})
+	}
+}
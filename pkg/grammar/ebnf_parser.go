@@ -115,10 +115,11 @@ func (p *ebnfParser) parseRule() (*Rule, error) {
 	// Collect comments before rule
 	comment := ""
 	for p.peek() != nil && p.peek().Kind() == TokenComment {
-		commentText := string(p.peek().Value())
-		// Strip // prefix
-		commentText = strings.TrimPrefix(commentText, "//")
-		commentText = strings.TrimSpace(commentText)
+		if err := p.peek().Err(); err != nil {
+			return nil, err
+		}
+		// The decoded value is the text without comment delimiters
+		commentText := strings.TrimSpace(p.peek().DecodedString())
 		if comment != "" {
 			comment += "\n"
 		}
@@ -405,30 +406,13 @@ func newEBNFTokenizer() tokenizer.Tokenizer {
 	)
 }
 
-// commentMatcher matches comments starting with //
+// commentMatcher matches // line comments and /* */ block comments
 func commentMatcher() tokenizer.Matcher {
-	return func(stream tokenizer.Stream) *tokenizer.Token {
-		// Check for //
-		r1, ok1 := stream.NextChar()
-		if !ok1 || r1 != '/' {
-			return nil
-		}
-		r2, ok2 := stream.NextChar()
-		if !ok2 || r2 != '/' {
-			return nil
-		}
-
-		value := []rune{'/', '/'}
-		// Read until newline
-		for {
-			r, ok := stream.NextChar()
-			if !ok || r == '\n' {
-				break
-			}
-			value = append(value, r)
-		}
-		return tokenizer.NewToken(TokenComment, value)
-	}
+	return tokenizer.CommentMatcherFunc(TokenComment, tokenizer.CommentOptions{
+		LinePrefixes: []string{"//"},
+		BlockOpen:    "/*",
+		BlockClose:   "*/",
+	})
 }
 
 // charClassMatcher matches character classes like [a-z] or [0-9]
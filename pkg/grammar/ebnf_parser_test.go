@@ -217,6 +217,25 @@ func TestParseEBNF_Comments(t *testing.T) {
 	}
 }
 
+func TestParseEBNF_BlockComments(t *testing.T) {
+	input := `
+		/* Boolean literal */
+		Value = "true" | "false" ;
+	`
+
+	grammar, err := ParseEBNF(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if grammar.Rules[0].Comment != "Boolean literal" {
+		t.Errorf("expected block comment text, got: %q", grammar.Rules[0].Comment)
+	}
+
+	if _, err := ParseEBNF(`/* never closed`); err == nil || !contains(err.Error(), "unterminated block comment") {
+		t.Errorf("expected unterminated comment error, got: %v", err)
+	}
+}
+
 func TestParseEBNF_ComplexExample(t *testing.T) {
 	input := `
 		// Object with properties
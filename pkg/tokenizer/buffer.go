@@ -0,0 +1,174 @@
+package tokenizer
+
+//
+// TokenBuffer - Cached multi-token lookahead over a Tokenizer
+//
+
+// tokenBufferInitialSize is the initial capacity of the TokenBuffer ring.
+// The ring grows by doubling when lookahead or outstanding marks require more room.
+const tokenBufferInitialSize = 16
+
+// TokenBuffer layers a ring buffer of lexed tokens over a Tokenizer.
+// It provides the arbitrary lookahead needed by LL(k) parsers: each token is
+// lexed exactly once, and Peek(k) is a constant-time lookup once the token has
+// been buffered.
+//
+// Backtracking is done by token index (Mark/Rewind) instead of cloning the
+// underlying stream. Tokens are retained from the oldest outstanding mark, so
+// rewinding never needs to re-lex input.
+//
+// A TokenBuffer takes ownership of the Tokenizer's position: once wrapped, the
+// tokenizer should not be advanced directly.
+type TokenBuffer struct {
+	tokenizer *Tokenizer
+	skip      map[string]bool // token kinds dropped before buffering
+
+	ring  []Token // circular storage for buffered tokens
+	head  int     // ring index of the token at absolute index base
+	count int     // number of tokens currently buffered
+	base  int     // absolute index of the oldest buffered token
+	pos   int     // absolute index of the next token to consume
+
+	marks []int // stack of marked token indices for rewinding
+	done  bool  // true once the tokenizer yields no more tokens
+}
+
+// NewTokenBuffer constructs a TokenBuffer reading from an initialized tokenizer.
+// Tokens whose kind is listed in skipKinds (e.g. "Whitespace") are discarded
+// before buffering and never returned by Peek or Next.
+func NewTokenBuffer(t *Tokenizer, skipKinds ...string) *TokenBuffer {
+	var skip map[string]bool
+	if len(skipKinds) > 0 {
+		skip = make(map[string]bool, len(skipKinds))
+		for _, kind := range skipKinds {
+			skip[kind] = true
+		}
+	}
+	return &TokenBuffer{
+		tokenizer: t,
+		skip:      skip,
+		ring:      make([]Token, tokenBufferInitialSize),
+		marks:     make([]int, 0),
+	}
+}
+
+// Peek returns the k-th upcoming token without consuming it.
+// Lookahead is 1-based: Peek(1) is the next token, Peek(2) the one after.
+// Returns nil, false if k < 1 or fewer than k tokens remain.
+func (b *TokenBuffer) Peek(k int) (*Token, bool) {
+	if k < 1 || !b.fill(b.pos+k-b.base) {
+		return nil, false
+	}
+	token := b.at(b.pos + k - 1)
+	return &token, true
+}
+
+// Next consumes and returns the next token.
+// Returns nil, false if no more tokens can be read.
+func (b *TokenBuffer) Next() (*Token, bool) {
+	if !b.fill(b.pos + 1 - b.base) {
+		return nil, false
+	}
+	token := b.at(b.pos)
+	b.pos++
+	b.discard()
+	return &token, true
+}
+
+// Index returns the absolute index of the next token to be consumed.
+// The first token of the input has index 0.
+func (b *TokenBuffer) Index() int {
+	return b.pos
+}
+
+// Mark pushes the current token index onto the marks stack for later rewinding.
+// Tokens from the marked index onward stay buffered until the mark is released.
+func (b *TokenBuffer) Mark() {
+	b.marks = append(b.marks, b.pos)
+}
+
+// Rewind restores the buffer to the most recently marked token index and pops the mark.
+// Returns false if there are no marks to rewind to.
+func (b *TokenBuffer) Rewind() bool {
+	if len(b.marks) == 0 {
+		return false
+	}
+	lastIdx := len(b.marks) - 1
+	b.pos = b.marks[lastIdx]
+	b.marks = b.marks[:lastIdx]
+	b.discard()
+	return true
+}
+
+// Release pops the most recent mark without rewinding, committing to the
+// tokens consumed since it was pushed.
+// Returns false if there are no marks to release.
+func (b *TokenBuffer) Release() bool {
+	if len(b.marks) == 0 {
+		return false
+	}
+	b.marks = b.marks[:len(b.marks)-1]
+	b.discard()
+	return true
+}
+
+// IsEos returns true if every token has been consumed and the underlying
+// stream is fully read. A tokenizer that stopped on unmatched input is not at EOS.
+func (b *TokenBuffer) IsEos() bool {
+	return !b.fill(b.pos+1-b.base) && b.tokenizer.stream.IsEos()
+}
+
+// at returns the buffered token at absolute index idx.
+// The caller must ensure base <= idx < base+count.
+func (b *TokenBuffer) at(idx int) Token {
+	return b.ring[(b.head+idx-b.base)%len(b.ring)]
+}
+
+// fill lexes tokens until at least n are buffered from base.
+// Returns false if the tokenizer runs out of tokens first.
+func (b *TokenBuffer) fill(n int) bool {
+	for b.count < n {
+		if b.done {
+			return false
+		}
+		token, ok := b.tokenizer.NextToken()
+		if !ok {
+			b.done = true
+			return false
+		}
+		if b.skip[token.kind] {
+			continue
+		}
+		b.push(*token)
+	}
+	return true
+}
+
+// push appends a token to the ring, growing it when full.
+func (b *TokenBuffer) push(token Token) {
+	if b.count == len(b.ring) {
+		grown := make([]Token, len(b.ring)*2)
+		for i := 0; i < b.count; i++ {
+			grown[i] = b.ring[(b.head+i)%len(b.ring)]
+		}
+		b.ring = grown
+		b.head = 0
+	}
+	b.ring[(b.head+b.count)%len(b.ring)] = token
+	b.count++
+}
+
+// discard drops buffered tokens that can no longer be reached, i.e. tokens
+// before both the current index and the oldest outstanding mark.
+func (b *TokenBuffer) discard() {
+	keep := b.pos
+	if len(b.marks) > 0 && b.marks[0] < keep {
+		keep = b.marks[0]
+	}
+	for b.base < keep && b.count > 0 {
+		b.ring[b.head] = Token{}
+		b.head = (b.head + 1) % len(b.ring)
+		b.base++
+		b.count--
+	}
+}
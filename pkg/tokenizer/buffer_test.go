@@ -0,0 +1,183 @@
+package tokenizer
+
+import (
+	"testing"
+)
+
+func newTestTokenBuffer(input string, skipKinds ...string) *TokenBuffer {
+	tokenizer := NewTokenizer(alphaMatcher, numericMatcher)
+	tokenizer.Initialize(input)
+	return NewTokenBuffer(&tokenizer, skipKinds...)
+}
+
+func TestTokenBufferPeekLookahead(t *testing.T) {
+	// Given
+	buffer := newTestTokenBuffer("abc 123 def", "Whitespace")
+
+	// When
+	third, ok3 := buffer.Peek(3)
+	first, ok1 := buffer.Peek(1)
+	second, ok2 := buffer.Peek(2)
+	_, ok4 := buffer.Peek(4)
+
+	// Then
+	if !ok1 || !ok2 || !ok3 {
+		t.Fatalf("Expected three tokens of lookahead")
+	}
+	if ok4 {
+		t.Fatalf("Expected Peek(4) to fail on three tokens")
+	}
+	if first.ValueString() != "abc" || second.ValueString() != "123" || third.ValueString() != "def" {
+		t.Fatalf("Unexpected lookahead: %s %s %s", first, second, third)
+	}
+	if third.Column() != 9 {
+		t.Fatalf("Expected third token at column 9, got %d", third.Column())
+	}
+	if buffer.Index() != 0 {
+		t.Fatalf("Expected Peek not to consume, index = %d", buffer.Index())
+	}
+}
+
+func TestTokenBufferPeekInvalidK(t *testing.T) {
+	buffer := newTestTokenBuffer("abc")
+
+	if token, ok := buffer.Peek(0); ok || token != nil {
+		t.Fatalf("Expected Peek(0) to fail, got %v", token)
+	}
+}
+
+func TestTokenBufferNext(t *testing.T) {
+	// Given
+	buffer := newTestTokenBuffer("abc123")
+
+	// When
+	token1, ok1 := buffer.Next()
+	token2, ok2 := buffer.Next()
+	_, ok3 := buffer.Next()
+
+	// Then
+	if !ok1 || !ok2 || ok3 {
+		t.Fatalf("Expected exactly two tokens, got %v %v %v", ok1, ok2, ok3)
+	}
+	if token1.Kind() != "Alpha" || token2.Kind() != "Numeric" {
+		t.Fatalf("Unexpected tokens: %s %s", token1, token2)
+	}
+	if !buffer.IsEos() {
+		t.Fatalf("Expected buffer to be at EOS")
+	}
+}
+
+func TestTokenBufferIsEosOnUnmatchedInput(t *testing.T) {
+	tokenizer := NewTokenizer(alphaMatcher)
+	tokenizer.Initialize("abc123")
+	buffer := NewTokenBuffer(&tokenizer)
+
+	buffer.Next()
+
+	if _, ok := buffer.Next(); ok {
+		t.Fatalf("Expected no token for unmatched input")
+	}
+	if buffer.IsEos() {
+		t.Fatalf("Expected IsEos to be false when input is not fully consumed")
+	}
+}
+
+func TestTokenBufferMarkAndRewind(t *testing.T) {
+	// Given
+	buffer := newTestTokenBuffer("a 1 b 2 c 3", "Whitespace")
+	buffer.Next()
+
+	// When - nested marks
+	buffer.Mark()
+	buffer.Next()
+	buffer.Mark()
+	buffer.Next()
+	buffer.Next()
+	outer := buffer.Rewind()
+	afterInner, _ := buffer.Peek(1)
+	inner := buffer.Rewind()
+	afterOuter, _ := buffer.Peek(1)
+
+	// Then
+	if !outer || !inner {
+		t.Fatalf("Expected both rewinds to succeed")
+	}
+	if afterInner.ValueString() != "b" {
+		t.Fatalf("Expected 'b' after inner rewind, got %s", afterInner)
+	}
+	if afterOuter.ValueString() != "1" {
+		t.Fatalf("Expected '1' after outer rewind, got %s", afterOuter)
+	}
+	if buffer.Rewind() {
+		t.Fatalf("Expected rewind to fail when no mark exists")
+	}
+}
+
+func TestTokenBufferRelease(t *testing.T) {
+	buffer := newTestTokenBuffer("a 1 b", "Whitespace")
+
+	buffer.Mark()
+	buffer.Next()
+	if !buffer.Release() {
+		t.Fatalf("Expected release to succeed")
+	}
+
+	if buffer.Rewind() {
+		t.Fatalf("Expected no mark after release")
+	}
+	if buffer.Release() {
+		t.Fatalf("Expected release to fail without marks")
+	}
+	token, _ := buffer.Next()
+	if token.ValueString() != "1" {
+		t.Fatalf("Expected '1' after release, got %s", token)
+	}
+}
+
+func TestTokenBufferGrowsAcrossMarks(t *testing.T) {
+	// Given - more tokens than the initial ring size
+	input := ""
+	for i := 0; i < tokenBufferInitialSize*3; i++ {
+		input += "a 1 "
+	}
+	buffer := newTestTokenBuffer(input, "Whitespace")
+
+	// When
+	buffer.Mark()
+	count := 0
+	for {
+		if _, ok := buffer.Next(); !ok {
+			break
+		}
+		count++
+	}
+	buffer.Rewind()
+	first, _ := buffer.Peek(1)
+	last, ok := buffer.Peek(count)
+
+	// Then
+	if count != tokenBufferInitialSize*6 {
+		t.Fatalf("Expected %d tokens, got %d", tokenBufferInitialSize*6, count)
+	}
+	if !ok || first.ValueString() != "a" || last.ValueString() != "1" {
+		t.Fatalf("Expected full replay after rewind, got %v ... %v", first, last)
+	}
+}
+
+func TestTokenBufferDiscardsConsumedTokens(t *testing.T) {
+	input := ""
+	for i := 0; i < tokenBufferInitialSize*4; i++ {
+		input += "a1"
+	}
+	buffer := newTestTokenBuffer(input)
+
+	for {
+		if _, ok := buffer.Next(); !ok {
+			break
+		}
+	}
+
+	if len(buffer.ring) != tokenBufferInitialSize {
+		t.Fatalf("Expected ring to stay at %d without marks, got %d", tokenBufferInitialSize, len(buffer.ring))
+	}
+}
@@ -0,0 +1,161 @@
+package tokenizer
+
+import "strings"
+
+//
+// Comments - Configurable line, block and documentation comments
+//
+
+// CommentOptions configures CommentMatcherFunc.
+type CommentOptions struct {
+	// LinePrefixes start comments that run to the end of the line (e.g. "//", "#")
+	LinePrefixes []string
+
+	// BlockOpen and BlockClose delimit block comments (e.g. "/*" and "*/").
+	// An empty BlockOpen disables block comments
+	BlockOpen  string
+	BlockClose string
+
+	// Nested allows block comments to contain other block comments, as in
+	// /* outer /* inner */ still outer */
+	Nested bool
+
+	// DocLinePrefixes mark line comments as documentation (e.g. "///"). A
+	// prefix followed by a repeat of its last character, such as "////", is
+	// an ordinary comment
+	DocLinePrefixes []string
+
+	// DocBlockOpen marks block comments as documentation (e.g. "/**"). As with
+	// line comments "/***" is ordinary, and so is the empty comment "/**/"
+	DocBlockOpen string
+
+	// DocKind is the token kind for documentation comments. Empty disables
+	// doc-comment detection
+	DocKind string
+}
+
+// DefaultCommentOptions returns C-style comments: // and non-nesting /* */,
+// with /// and /** */ reported as "DocComment" tokens.
+func DefaultCommentOptions() CommentOptions {
+	return CommentOptions{
+		LinePrefixes:    []string{"//"},
+		BlockOpen:       "/*",
+		BlockClose:      "*/",
+		DocLinePrefixes: []string{"///"},
+		DocBlockOpen:    "/**",
+		DocKind:         "DocComment",
+	}
+}
+
+// CommentMatcherFunc creates a matcher for line and block comments.
+//
+// The token value is the whole comment including its delimiters; a line
+// comment stops before the line terminator. The decoded value is the text
+// between the delimiters, available from Token.DecodedString, so tools can
+// extract documentation without knowing the syntax. Comments matching the doc
+// markers get options.DocKind instead of tokenName. An unterminated block
+// comment still yields a token running to the end of input, whose Err method
+// returns a *PositionError at the comment start.
+//
+// Example:
+//
+//	options := DefaultCommentOptions()
+//	options.Nested = true
+//	matcher := CommentMatcherFunc("Comment", options)
+//	// "/* a /* b */ c */" -> [Comment: "/* a /* b */ c */"], DecodedString() " a /* b */ c "
+//	// "/// Docs"          -> [DocComment: "/// Docs"], DecodedString() " Docs"
+func CommentMatcherFunc(tokenName string, options CommentOptions) Matcher {
+	linePrefixes := append([]string(nil), options.LinePrefixes...)
+	docLinePrefixes := append([]string(nil), options.DocLinePrefixes...)
+	open, closing := []rune(options.BlockOpen), []rune(options.BlockClose)
+
+	return func(stream Stream) *Token {
+		start := stream.GetLocation()
+
+		for _, prefix := range linePrefixes {
+			if !matchRunes(stream, prefix) {
+				stream.SetLocation(start)
+				continue
+			}
+			value := []rune(prefix)
+			for {
+				r, ok := stream.PeekChar()
+				if !ok || r == '\n' || r == '\r' {
+					break
+				}
+				stream.NextChar()
+				value = append(value, r)
+			}
+			text := string(value)
+			if options.DocKind != "" {
+				for _, doc := range docLinePrefixes {
+					if isDocComment(text, doc) {
+						return NewDecodedToken(options.DocKind, value, text[len(doc):])
+					}
+				}
+			}
+			return NewDecodedToken(tokenName, value, text[len(prefix):])
+		}
+
+		if len(open) == 0 || !matchRunes(stream, options.BlockOpen) {
+			return nil
+		}
+		value := append([]rune(nil), open...)
+		scanFrom := len(value) // Delimiters are not reused: "/*/" does not close
+		for depth := 1; depth > 0; {
+			r, ok := stream.NextChar()
+			if !ok {
+				pos := NewPosition(start.Cursor, start.Row, start.Column)
+				return NewDecodedToken(tokenName, value, NewPositionError(pos, "unterminated block comment"))
+			}
+			value = append(value, r)
+			if hasRunesSuffix(value[scanFrom:], closing) {
+				depth--
+				scanFrom = len(value)
+			} else if options.Nested && hasRunesSuffix(value[scanFrom:], open) {
+				depth++
+				scanFrom = len(value)
+			}
+		}
+
+		text := string(value)
+		if options.DocKind != "" && options.DocBlockOpen != "" &&
+			text != options.BlockOpen+options.BlockClose && isDocComment(text, options.DocBlockOpen) {
+			return NewDecodedToken(options.DocKind, value, text[len(options.DocBlockOpen):len(text)-len(options.BlockClose)])
+		}
+		return NewDecodedToken(tokenName, value, text[len(options.BlockOpen):len(text)-len(options.BlockClose)])
+	}
+}
+
+// matchRunes consumes prefix from the stream, reporting whether it matched.
+func matchRunes(stream Stream, prefix string) bool {
+	for _, want := range prefix {
+		if r, ok := stream.NextChar(); !ok || r != want {
+			return false
+		}
+	}
+	return prefix != ""
+}
+
+func hasRunesSuffix(value, suffix []rune) bool {
+	if len(suffix) == 0 || len(value) < len(suffix) {
+		return false
+	}
+	tail := value[len(value)-len(suffix):]
+	for i := range suffix {
+		if tail[i] != suffix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// isDocComment reports whether text starts with the doc marker and is not
+// just the marker's last character repeated, as in "////" or "/***".
+func isDocComment(text, marker string) bool {
+	if marker == "" || !strings.HasPrefix(text, marker) {
+		return false
+	}
+	rest := text[len(marker):]
+	return rest == "" || rest[0] != marker[len(marker)-1]
+}
@@ -0,0 +1,127 @@
+package tokenizer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCommentMatcher(t *testing.T) {
+	tokenizer := NewTokenizer(
+		CommentMatcherFunc("Comment", DefaultCommentOptions()),
+		IdentifierMatcherFunc("Identifier", IdentifierOptions{}),
+	)
+	tokenizer.Initialize("// line\nx /* block */ y\n/// docs\n//// rule\n/** api */ /**/ /*** banner ***/")
+
+	actual := tokenizer.TokenizeToString("\n")
+	expected := StripMargin(`
+		|[Comment: "// line"]
+		|[Whitespace: "\n"]
+		|[Identifier: "x"]
+		|[Whitespace: " "]
+		|[Comment: "/* block */"]
+		|[Whitespace: " "]
+		|[Identifier: "y"]
+		|[Whitespace: "\n"]
+		|[DocComment: "/// docs"]
+		|[Whitespace: "\n"]
+		|[Comment: "//// rule"]
+		|[Whitespace: "\n"]
+		|[DocComment: "/** api */"]
+		|[Whitespace: " "]
+		|[Comment: "/**/"]
+		|[Whitespace: " "]
+		|[Comment: "/*** banner ***/"]
+		|[EOS]`)
+
+	diff, tdOk := Diff(expected, actual)
+	if !tdOk {
+		t.Fatalf("Tokenization validation error: \n%v", diff)
+	}
+}
+
+func TestCommentMatcherText(t *testing.T) {
+	nested := DefaultCommentOptions()
+	nested.Nested = true
+
+	shell := CommentOptions{LinePrefixes: []string{"#", "--"}}
+
+	tests := []struct {
+		name    string
+		options CommentOptions
+		input   string
+		kind    string
+		raw     string
+		text    string
+	}{
+		{"line", DefaultCommentOptions(), "// hello\r\nnext", "Comment", "// hello", " hello"},
+		{"doc line", DefaultCommentOptions(), "/// Returns x", "DocComment", "/// Returns x", " Returns x"},
+		{"block", DefaultCommentOptions(), "/* a\n b */ rest", "Comment", "/* a\n b */", " a\n b "},
+		{"block close overlaps open", DefaultCommentOptions(), "/*/ x */", "Comment", "/*/ x */", "/ x "},
+		{"doc block", DefaultCommentOptions(), "/** Docs */", "DocComment", "/** Docs */", " Docs "},
+		{"flat block ignores inner opener", DefaultCommentOptions(), "/* a /* b */ c */", "Comment", "/* a /* b */", " a /* b "},
+		{"nested block", nested, "/* a /* b */ c */ d", "Comment", "/* a /* b */ c */", " a /* b */ c "},
+		{"nested doc block", nested, "/** a /* b */ */", "DocComment", "/** a /* b */ */", " a /* b */ "},
+		{"multiple prefixes", shell, "-- sql style", "Comment", "-- sql style", " sql style"},
+		{"doc detection disabled", shell, "## heading", "Comment", "## heading", "# heading"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := CommentMatcherFunc("Comment", tt.options)(NewStream(tt.input))
+			if token == nil {
+				t.Fatal("Expected a comment token")
+			}
+			if token.Err() != nil {
+				t.Fatalf("Unexpected error: %v", token.Err())
+			}
+			if token.Kind() != tt.kind {
+				t.Errorf("Kind() = %q, want %q", token.Kind(), tt.kind)
+			}
+			if token.ValueString() != tt.raw {
+				t.Errorf("ValueString() = %q, want %q", token.ValueString(), tt.raw)
+			}
+			if token.DecodedString() != tt.text {
+				t.Errorf("DecodedString() = %q, want %q", token.DecodedString(), tt.text)
+			}
+		})
+	}
+}
+
+func TestCommentMatcherNoMatch(t *testing.T) {
+	matcher := CommentMatcherFunc("Comment", DefaultCommentOptions())
+	for _, input := range []string{"", "/", "/ *", "x // y", "*/"} {
+		if token := matcher(NewStream(input)); token != nil {
+			t.Errorf("matcher(%q) = %v, want nil", input, token)
+		}
+	}
+
+	lineOnly := CommentMatcherFunc("Comment", CommentOptions{LinePrefixes: []string{"#"}})
+	if token := lineOnly(NewStream("/* x */")); token != nil {
+		t.Errorf("Expected block comments to be disabled, got %v", token)
+	}
+}
+
+func TestCommentMatcherUnterminated(t *testing.T) {
+	options := DefaultCommentOptions()
+	options.Nested = true
+
+	tokenizer := NewTokenizer(CommentMatcherFunc("Comment", options))
+	tokenizer.Initialize("\n  /* outer /* inner */ never closed")
+
+	tokenizer.NextToken() // Whitespace
+	token, ok := tokenizer.NextToken()
+	if !ok {
+		t.Fatal("Expected a comment token")
+	}
+	if token.ValueString() != "/* outer /* inner */ never closed" {
+		t.Errorf("ValueString() = %q, want the rest of the input", token.ValueString())
+	}
+
+	var posErr *PositionError
+	if !errors.As(token.Err(), &posErr) {
+		t.Fatalf("Expected *PositionError, got %v", token.Err())
+	}
+	if want := "error at line 2, column 3: unterminated block comment"; posErr.Error() != want {
+		t.Errorf("Err() = %q, want %q", posErr.Error(), want)
+	}
+}
@@ -0,0 +1,53 @@
+package tokenizer
+
+import "context"
+
+//
+// Context Support - Cancellable tokenization for long-running inputs
+//
+
+// NextTokenContext is like NextToken but first checks ctx for cancellation.
+// If ctx is done, it returns a *PositionError wrapping ctx.Err() and carrying
+// the position reached; errors.Is(err, context.Canceled) and
+// errors.Is(err, context.DeadlineExceeded) work as expected.
+func (t *Tokenizer) NextTokenContext(ctx context.Context) (*Token, bool, error) {
+	if err := t.contextErr(ctx); err != nil {
+		return nil, false, err
+	}
+	token, ok := t.NextToken()
+	return token, ok, nil
+}
+
+// TokenizeContext is like Tokenize but stops when ctx is cancelled.
+// Cancellation is checked before every token, so a cancelled context stops
+// lexing of reader-backed streams promptly.
+// Returns the tokens read so far, whether EOS was reached, and a
+// *PositionError wrapping ctx.Err() if tokenization was cancelled.
+func (t *Tokenizer) TokenizeContext(ctx context.Context) ([]Token, bool, error) {
+	tokens := make([]Token, 0)
+	for {
+		token, ok, err := t.NextTokenContext(ctx)
+		if err != nil {
+			return tokens, false, err
+		}
+		if !ok {
+			break
+		}
+		tokens = append(tokens, *token)
+	}
+	return tokens, t.stream.IsEos(), nil
+}
+
+// contextErr returns a positioned error if ctx is done.
+// A non-blocking receive on Done keeps the check cheap enough for every token.
+func (t *Tokenizer) contextErr(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return &PositionError{
+			Position: NewPosition(t.stream.GetOffset(), t.stream.GetRow(), t.stream.GetColumn()),
+			Err:      ctx.Err(),
+		}
+	default:
+		return nil
+	}
+}
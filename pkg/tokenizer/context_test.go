@@ -0,0 +1,106 @@
+package tokenizer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// endlessReader yields "abc 123\n" forever and cancels after a number of reads.
+type endlessReader struct {
+	reads  int
+	cancel context.CancelFunc
+	after  int
+}
+
+func (r *endlessReader) Read(p []byte) (int, error) {
+	r.reads++
+	if r.reads == r.after {
+		r.cancel()
+	}
+	chunk := "abc 123\n"
+	n := 0
+	for n+len(chunk) <= len(p) {
+		n += copy(p[n:], chunk)
+	}
+	return n, nil
+}
+
+func TestTokenizeContextCompletes(t *testing.T) {
+	// Given
+	tokenizer := NewTokenizer(alphaMatcher, numericMatcher)
+	tokenizer.Initialize("abc 123")
+
+	// When
+	tokens, eos, err := tokenizer.TokenizeContext(context.Background())
+
+	// Then
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !eos || len(tokens) != 3 {
+		t.Fatalf("Expected 3 tokens and EOS, got %d tokens, eos=%v", len(tokens), eos)
+	}
+}
+
+func TestTokenizeContextCancelsReaderStream(t *testing.T) {
+	// Given
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reader := &endlessReader{cancel: cancel, after: 4}
+	tokenizer := NewTokenizer(alphaMatcher, numericMatcher)
+	tokenizer.InitializeFromStream(NewStreamFromReader(reader))
+
+	// When
+	tokens, eos, err := tokenizer.TokenizeContext(ctx)
+
+	// Then
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if eos || len(tokens) == 0 {
+		t.Fatalf("Expected partial tokens without EOS, got %d tokens, eos=%v", len(tokens), eos)
+	}
+
+	var posErr *PositionError
+	if !errors.As(err, &posErr) {
+		t.Fatalf("Expected *PositionError, got %T", err)
+	}
+	last := tokens[len(tokens)-1]
+	if posErr.Position.Offset != last.Offset()+len(last.Value()) {
+		t.Fatalf("Expected position after last token, got %v", posErr.Position)
+	}
+	if !strings.Contains(err.Error(), "context canceled") {
+		t.Fatalf("Expected error message to mention cancellation, got %q", err.Error())
+	}
+}
+
+func TestNextTokenContextDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	tokenizer := NewTokenizer(alphaMatcher)
+	tokenizer.Initialize("abc")
+
+	token, ok, err := tokenizer.NextTokenContext(ctx)
+	if token != nil || ok || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline error, got %v %v %v", token, ok, err)
+	}
+	if err.Error() != "error at line 1, column 1: context deadline exceeded" {
+		t.Fatalf("Unexpected error message: %q", err.Error())
+	}
+}
+
+func TestPositionErrorWithoutPosition(t *testing.T) {
+	err := NewPositionError(NewPosition(-1, -1, -1), "bad input")
+
+	if err.Error() != "bad input" {
+		t.Fatalf("Expected bare message, got %q", err.Error())
+	}
+	if err.Unwrap() != nil {
+		t.Fatalf("Expected no underlying cause")
+	}
+}
@@ -0,0 +1,61 @@
+package tokenizer
+
+//
+// Definition - Immutable, shareable tokenizer configuration
+//
+
+// Definition is an immutable tokenizer configuration: the ordered list of
+// matchers used to lex input. Unlike Tokenizer, a Definition holds no stream
+// or mark state, so one Definition can be built once and shared across
+// goroutines, with each goroutine lexing its own input through a lightweight
+// Tokenizer obtained from NewTokenizer or NewTokenizerFromStream.
+//
+// Sharing is only safe if the matchers themselves are stateless. All built-in
+// matchers are; custom matchers must not mutate captured variables.
+type Definition struct {
+	matchers []Matcher
+}
+
+// NewDefinition constructs a Definition with the given matchers.
+// WhiteSpaceMatcher is automatically prepended to consume whitespace,
+// mirroring NewTokenizer.
+func NewDefinition(matchers ...Matcher) *Definition {
+	newMatchers := make([]Matcher, 0, len(matchers)+1)
+	newMatchers = append(newMatchers, WhiteSpaceMatcher)
+	newMatchers = append(newMatchers, matchers...)
+	return &Definition{matchers: newMatchers}
+}
+
+// NewDefinitionWithoutWhitespace constructs a Definition with the given matchers
+// WITHOUT automatically prepending WhiteSpaceMatcher, mirroring
+// NewTokenizerWithoutWhitespace.
+func NewDefinitionWithoutWhitespace(matchers ...Matcher) *Definition {
+	newMatchers := make([]Matcher, len(matchers))
+	copy(newMatchers, matchers)
+	return &Definition{matchers: newMatchers}
+}
+
+// NewTokenizer returns a Tokenizer for this definition initialized with input.
+// The returned Tokenizer owns its stream and marks and must not be shared.
+func (d *Definition) NewTokenizer(input string) Tokenizer {
+	return d.NewTokenizerFromStream(NewStream(input))
+}
+
+// NewTokenizerFromStream returns a Tokenizer for this definition initialized
+// with a pre-configured stream, such as one created with NewStreamFromReader.
+// The returned Tokenizer owns its stream and marks and must not be shared.
+func (d *Definition) NewTokenizerFromStream(stream Stream) Tokenizer {
+	return Tokenizer{
+		matchers: d.matchers,
+		stream:   stream,
+		marks:    make([]Stream, 0),
+	}
+}
+
+// Definition returns the immutable configuration of this tokenizer, which can
+// be shared to create further tokenizers with the same matchers.
+func (t *Tokenizer) Definition() *Definition {
+	matchers := make([]Matcher, len(t.matchers))
+	copy(matchers, t.matchers)
+	return &Definition{matchers: matchers}
+}
@@ -0,0 +1,117 @@
+package tokenizer
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestDefinitionNewTokenizer(t *testing.T) {
+	// Given
+	definition := NewDefinition(alphaMatcher, numericMatcher)
+
+	// When
+	tokenizer := definition.NewTokenizer("abc 123")
+	actual := tokenizer.TokenizeToString("\n")
+
+	// Then
+	expected := StripMargin(`
+		|[Alpha: "abc"]
+		|[Whitespace: " "]
+		|[Numeric: "123"]
+		|[EOS]
+	`)
+
+	diff, tdOk := Diff(expected, actual)
+	if !tdOk {
+		t.Fatalf("Tokenization validation error: \n%v", diff)
+	}
+}
+
+func TestDefinitionWithoutWhitespace(t *testing.T) {
+	definition := NewDefinitionWithoutWhitespace(alphaMatcher, numericMatcher)
+
+	tokenizer := definition.NewTokenizerFromStream(NewStreamFromReader(strings.NewReader("abc 123")))
+	actual := tokenizer.TokenizeToString("\n")
+
+	expected := StripMargin(`
+		|[Alpha: "abc"]
+		|[Stream...]
+	`)
+
+	diff, tdOk := Diff(expected, actual)
+	if !tdOk {
+		t.Fatalf("Tokenization validation error: \n%v", diff)
+	}
+}
+
+func TestDefinitionIsIsolatedFromCallerSlice(t *testing.T) {
+	// Given
+	matchers := []Matcher{alphaMatcher}
+	definition := NewDefinitionWithoutWhitespace(matchers...)
+
+	// When - caller mutates its slice after construction
+	matchers[0] = numericMatcher
+
+	// Then
+	tokenizer := definition.NewTokenizer("abc")
+	token, ok := tokenizer.NextToken()
+	if !ok || token.Kind() != "Alpha" {
+		t.Fatalf("Expected definition to keep its own matchers, got %v", token)
+	}
+}
+
+func TestTokenizerDefinitionRoundTrip(t *testing.T) {
+	original := NewTokenizer(alphaMatcher)
+	original.Initialize("abc")
+	original.NextToken()
+
+	tokenizer := original.Definition().NewTokenizer("def")
+	token, ok := tokenizer.NextToken()
+	if !ok || token.ValueString() != "def" {
+		t.Fatalf("Expected fresh tokenizer from definition, got %v", token)
+	}
+}
+
+// TestDefinitionConcurrentUse shares one Definition across goroutines.
+// Run with -race to verify that per-input tokenizers do not share mutable state.
+func TestDefinitionConcurrentUse(t *testing.T) {
+	definition := NewDefinition(alphaMatcher, numericMatcher)
+
+	const workers = 16
+	const iterations = 50
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				input := fmt.Sprintf("worker %d iteration %d", w, i)
+				tokenizer := definition.NewTokenizer(input)
+
+				tokenizer.Mark()
+				tokenizer.NextToken()
+				tokenizer.Rewind()
+
+				tokens, eos := tokenizer.Tokenize()
+				var sb strings.Builder
+				for _, token := range tokens {
+					sb.WriteString(token.ValueString())
+				}
+				if !eos || sb.String() != input {
+					errs <- fmt.Errorf("worker %d: got %q, want %q", w, sb.String(), input)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,455 @@
+package tokenizer
+
+import (
+	"fmt"
+	"strings"
+)
+
+//
+// Line Diff - LCS-based comparison with unified-diff output
+//
+// Diff compares lines positionally and stops at the first mismatch, which is
+// compact for short token dumps. UnifiedDiff aligns both inputs on their longest
+// common subsequence (Myers, or patience diff) so an inserted or removed line
+// only affects its own hunk.
+//
+
+// DiffAlgorithm selects how DiffLines aligns the two inputs.
+type DiffAlgorithm int
+
+const (
+	// DiffMyers finds a minimal edit script (Myers, "An O(ND) Difference Algorithm", 1986)
+	DiffMyers DiffAlgorithm = iota
+	// DiffPatience anchors on lines unique to both inputs, then falls back to Myers
+	// between anchors; it often keeps moved blocks and braces better aligned
+	DiffPatience
+)
+
+// EditOp is the kind of a LineEdit.
+type EditOp int
+
+// Edit operations.
+const (
+	EditEqual  EditOp = iota // Line present in both inputs
+	EditDelete               // Line only in expected
+	EditInsert               // Line only in actual
+)
+
+// LineEdit is one step of an edit script turning expected into actual.
+type LineEdit struct {
+	Op       EditOp
+	Text     string
+	Expected int // 0-based line index in expected, or -1 for EditInsert
+	Actual   int // 0-based line index in actual, or -1 for EditDelete
+}
+
+// DiffOptions configures UnifiedDiffWithOptions.
+type DiffOptions struct {
+	// Algorithm selects the alignment algorithm (default: DiffMyers)
+	Algorithm DiffAlgorithm
+
+	// Context is the number of unchanged lines shown around each change (default: 3)
+	Context int
+
+	// ExpectedName and ActualName label the "---" and "+++" header lines
+	ExpectedName string
+	ActualName   string
+}
+
+// DefaultDiffOptions returns the options used by UnifiedDiff.
+func DefaultDiffOptions() DiffOptions {
+	return DiffOptions{
+		Algorithm:    DiffMyers,
+		Context:      3,
+		ExpectedName: "Expected",
+		ActualName:   "Actual",
+	}
+}
+
+// UnifiedDiff compares two strings line by line and outputs a unified diff.
+// Returns the diff string and true if the strings matched; the diff is empty
+// when they match.
+//
+// Tabs are shown as ␉, empty changed lines as ␤, and within each pair of
+// changed lines the whitespace around the first difference is made visible
+// as in SpaceDiff.
+//
+// Example:
+//
+//	UnifiedDiff("a\nb\nc", "a\nx\nb\nc")
+//	--- Expected
+//	+++ Actual
+//	@@ -1,3 +1,4 @@
+//	 a
+//	+x
+//	 b
+//	 c
+func UnifiedDiff(expected string, actual string) (string, bool) {
+	return UnifiedDiffWithOptions(expected, actual, DefaultDiffOptions())
+}
+
+// UnifiedDiffWithOptions is UnifiedDiff with a configurable algorithm, context
+// size and header labels.
+func UnifiedDiffWithOptions(expected string, actual string, options DiffOptions) (string, bool) {
+	if expected == actual {
+		return ``, true
+	}
+
+	edits := DiffLines(strings.Split(expected, "\n"), strings.Split(actual, "\n"), options.Algorithm)
+	context := maxInt(options.Context, 0)
+
+	var sb strings.Builder
+	sb.WriteString("--- " + options.ExpectedName + "\n")
+	sb.WriteString("+++ " + options.ActualName + "\n")
+	beforeA, beforeB, next := 0, 0, 0
+	for _, hunk := range diffHunks(edits, context) {
+		for ; next < hunk[0]; next++ {
+			if edits[next].Op != EditInsert {
+				beforeA++
+			}
+			if edits[next].Op != EditDelete {
+				beforeB++
+			}
+		}
+		writeHunk(&sb, edits[hunk[0]:hunk[1]], beforeA, beforeB)
+	}
+	return sb.String(), false
+}
+
+// DiffLines returns the edit script turning expected into actual.
+func DiffLines(expected, actual []string, algorithm DiffAlgorithm) []LineEdit {
+	// Compare integer ids instead of strings
+	ids := make(map[string]int)
+	a, b := lineIDs(expected, ids), lineIDs(actual, ids)
+
+	var ops []EditOp
+	if algorithm == DiffPatience {
+		ops = patienceDiff(a, b, ops)
+	} else {
+		ops = myersDiff(a, b, ops)
+	}
+
+	edits := make([]LineEdit, 0, len(ops))
+	i, j := 0, 0
+	for _, op := range ops {
+		switch op {
+		case EditEqual:
+			edits = append(edits, LineEdit{Op: op, Text: expected[i], Expected: i, Actual: j})
+			i++
+			j++
+		case EditDelete:
+			edits = append(edits, LineEdit{Op: op, Text: expected[i], Expected: i, Actual: -1})
+			i++
+		case EditInsert:
+			edits = append(edits, LineEdit{Op: op, Text: actual[j], Expected: -1, Actual: j})
+			j++
+		}
+	}
+	return edits
+}
+
+func lineIDs(lines []string, ids map[string]int) []int {
+	result := make([]int, len(lines))
+	for i, line := range lines {
+		id, ok := ids[line]
+		if !ok {
+			id = len(ids)
+			ids[line] = id
+		}
+		result[i] = id
+	}
+	return result
+}
+
+// trimCommon returns the lengths of the common prefix and suffix of a and b.
+func trimCommon(a, b []int) (int, int) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	return prefix, suffix
+}
+
+func appendOps(ops []EditOp, op EditOp, n int) []EditOp {
+	for ; n > 0; n-- {
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+// myersDiff appends a minimal edit script for a -> b to ops.
+func myersDiff(a, b []int, ops []EditOp) []EditOp {
+	prefix, suffix := trimCommon(a, b)
+	ops = appendOps(ops, EditEqual, prefix)
+	ops = myersMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], ops)
+	return appendOps(ops, EditEqual, suffix)
+}
+
+func myersMiddle(a, b []int, ops []EditOp) []EditOp {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		ops = appendOps(ops, EditDelete, n)
+		return appendOps(ops, EditInsert, m)
+	}
+
+	// v[k+offset] is the furthest x reached on diagonal k = x - y
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	// trace[d] holds v[-d-1 .. d+1] as it was before round d
+	var trace [][]int
+
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // Down: insertion
+			} else {
+				x = v[offset+k-1] + 1 // Right: deletion
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return append(ops, myersBacktrack(trace, n, m)...)
+			}
+		}
+	}
+	return ops // Unreachable: d = n + m always reaches the end
+}
+
+// myersBacktrack walks the saved rounds from (n, m) back to the origin.
+func myersBacktrack(trace [][]int, n, m int) []EditOp {
+	var reversed []EditOp
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d+1] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, EditEqual)
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, EditInsert)
+			} else {
+				reversed = append(reversed, EditDelete)
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	ops := make([]EditOp, len(reversed))
+	for i, op := range reversed {
+		ops[len(reversed)-1-i] = op
+	}
+	return ops
+}
+
+// patienceDiff appends an edit script for a -> b to ops, anchored on the
+// longest increasing run of lines that occur exactly once in both inputs.
+func patienceDiff(a, b []int, ops []EditOp) []EditOp {
+	prefix, suffix := trimCommon(a, b)
+	ops = appendOps(ops, EditEqual, prefix)
+	a, b = a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	anchors := patienceAnchors(a, b)
+	if len(anchors) == 0 {
+		ops = myersMiddle(a, b, ops)
+		return appendOps(ops, EditEqual, suffix)
+	}
+
+	i, j := 0, 0
+	for _, anchor := range anchors {
+		ops = patienceDiff(a[i:anchor[0]], b[j:anchor[1]], ops)
+		ops = append(ops, EditEqual)
+		i, j = anchor[0]+1, anchor[1]+1
+	}
+	ops = patienceDiff(a[i:], b[j:], ops)
+	return appendOps(ops, EditEqual, suffix)
+}
+
+// patienceAnchors returns index pairs of lines unique to both a and b that
+// form the longest sequence increasing in both inputs.
+func patienceAnchors(a, b []int) [][2]int {
+	type occurrence struct {
+		countA, countB int
+		indexA, indexB int
+	}
+	lines := make(map[int]*occurrence)
+	for i, id := range a {
+		o := lines[id]
+		if o == nil {
+			o = &occurrence{}
+			lines[id] = o
+		}
+		o.countA++
+		o.indexA = i
+	}
+	for j, id := range b {
+		if o := lines[id]; o != nil {
+			o.countB++
+			o.indexB = j
+		}
+	}
+
+	// Unique matches in order of a
+	var matches [][2]int
+	for i, id := range a {
+		if o := lines[id]; o.countA == 1 && o.countB == 1 {
+			matches = append(matches, [2]int{i, o.indexB})
+		}
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+
+	// Longest increasing subsequence on b indexes via patience sorting
+	var tops []int // Index into matches of the top card of each pile
+	prev := make([]int, len(matches))
+	for m, match := range matches {
+		lo, hi := 0, len(tops)
+		for lo < hi {
+			mid := (lo + hi) / 2
+			if matches[tops[mid]][1] < match[1] {
+				lo = mid + 1
+			} else {
+				hi = mid
+			}
+		}
+		prev[m] = -1
+		if lo > 0 {
+			prev[m] = tops[lo-1]
+		}
+		if lo == len(tops) {
+			tops = append(tops, m)
+		} else {
+			tops[lo] = m
+		}
+	}
+
+	anchors := make([][2]int, len(tops))
+	for m, i := tops[len(tops)-1], len(tops)-1; m >= 0; m, i = prev[m], i-1 {
+		anchors[i] = matches[m]
+	}
+	return anchors
+}
+
+// diffHunks returns [start, end) ranges of edits to print, each covering one
+// or more changes plus up to context unchanged lines on either side.
+func diffHunks(edits []LineEdit, context int) [][2]int {
+	var hunks [][2]int
+	for i := 0; i < len(edits); {
+		if edits[i].Op == EditEqual {
+			i++
+			continue
+		}
+		start := maxInt(i-context, 0)
+		end := i
+		// Extend while the next change is within 2*context unchanged lines
+		for end < len(edits) {
+			if edits[end].Op != EditEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(edits) && edits[run].Op == EditEqual {
+				run++
+			}
+			if run == len(edits) || run-end > 2*context {
+				end = minInt(end+context, len(edits))
+				break
+			}
+			end = run
+		}
+		hunks = append(hunks, [2]int{start, end})
+		i = end
+	}
+	return hunks
+}
+
+// writeHunk writes one hunk; beforeA and beforeB count the expected and actual
+// lines preceding it.
+func writeHunk(sb *strings.Builder, edits []LineEdit, beforeA, beforeB int) {
+	countA, countB := 0, 0
+	for _, e := range edits {
+		if e.Op != EditInsert {
+			countA++
+		}
+		if e.Op != EditDelete {
+			countB++
+		}
+	}
+	sb.WriteString("@@ -" + hunkRange(beforeA, countA) + " +" + hunkRange(beforeB, countB) + " @@\n")
+
+	for i := 0; i < len(edits); {
+		if edits[i].Op == EditEqual {
+			sb.WriteString(" " + showTabs(edits[i].Text) + "\n")
+			i++
+			continue
+		}
+		// A block of deletions followed by insertions; pair them up for SpaceDiff
+		dels := i
+		for i < len(edits) && edits[i].Op == EditDelete {
+			i++
+		}
+		ins := i
+		for i < len(edits) && edits[i].Op == EditInsert {
+			i++
+		}
+		deleted, inserted := edits[dels:ins], edits[ins:i]
+		paired := minInt(len(deleted), len(inserted))
+		for k, e := range deleted {
+			text := e.Text
+			if k < paired {
+				text, _ = SpaceDiff(e.Text, inserted[k].Text)
+			}
+			sb.WriteString("-" + showChangedLine(text) + "\n")
+		}
+		for k, e := range inserted {
+			text := e.Text
+			if k < paired {
+				_, text = SpaceDiff(deleted[k].Text, e.Text)
+			}
+			sb.WriteString("+" + showChangedLine(text) + "\n")
+		}
+	}
+}
+
+// hunkRange formats a unified diff range; an empty range names the line it follows.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
+
+func showChangedLine(text string) string {
+	if text == `` {
+		return `␤`
+	}
+	return showTabs(text)
+}
@@ -0,0 +1,179 @@
+package tokenizer
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiffEqual(t *testing.T) {
+	diff, ok := UnifiedDiff("a\nb", "a\nb")
+	if !ok || diff != `` {
+		t.Errorf("UnifiedDiff() = %q, %v; want empty, true", diff, ok)
+	}
+}
+
+func TestUnifiedDiffInsertedLine(t *testing.T) {
+	expected := "a\nb\nc\nd\ne\nf\ng\nh\ni"
+	actual := "a\nb\nc\nd\nX\ne\nf\ng\nh\ni"
+
+	diff, ok := UnifiedDiff(expected, actual)
+	if ok {
+		t.Fatal("Expected mismatch")
+	}
+	want := StripMargin(`
+		|--- Expected
+		|+++ Actual
+		|@@ -2,6 +2,7 @@
+		| b
+		| c
+		| d
+		|+X
+		| e
+		| f
+		| g
+		|`)
+	if diff != want {
+		t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", diff, want)
+	}
+}
+
+func TestUnifiedDiffSeparateHunks(t *testing.T) {
+	expected := strings.Join([]string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}, "\n")
+	actual := strings.Join([]string{"one", "2", "3", "4", "5", "6", "7", "8", "9"}, "\n")
+
+	options := DefaultDiffOptions()
+	options.Context = 1
+	diff, _ := UnifiedDiffWithOptions(expected, actual, options)
+	want := StripMargin(`
+		|--- Expected
+		|+++ Actual
+		|@@ -1,2 +1,2 @@
+		|-1
+		|+one
+		| 2
+		|@@ -9,2 +9 @@
+		| 9
+		|-10
+		|`)
+	if diff != want {
+		t.Errorf("UnifiedDiffWithOptions() =\n%s\nwant\n%s", diff, want)
+	}
+}
+
+func TestUnifiedDiffEmptyRange(t *testing.T) {
+	options := DefaultDiffOptions()
+	options.Context = 0
+	diff, _ := UnifiedDiffWithOptions("a\nb", "a\nx\nb", options)
+	if !strings.Contains(diff, "@@ -1,0 +2 @@\n+x\n") {
+		t.Errorf("Expected empty expected range after line 1, got\n%s", diff)
+	}
+}
+
+func TestUnifiedDiffShowsInvisible(t *testing.T) {
+	diff, _ := UnifiedDiff("key:\tvalue\nend", "key:  value\nend\n")
+	want := StripMargin(`
+		|--- Expected
+		|+++ Actual
+		|@@ -1,2 +1,3 @@
+		|-key:␉value
+		|+key:␣␣value
+		| end
+		|+␤
+		|`)
+	if diff != want {
+		t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", diff, want)
+	}
+}
+
+func TestDiffLinesPatience(t *testing.T) {
+	// Myers matches the shared "}" lines; patience anchors on the unique function headers
+	expected := []string{"func a() {", "  x", "}", "func b() {", "  y", "}"}
+	actual := []string{"func b() {", "  y", "}", "func a() {", "  x", "}"}
+
+	edits := DiffLines(expected, actual, DiffPatience)
+	checkEdits(t, expected, actual, edits)
+	if edits[0].Op != EditInsert && edits[0].Op != EditDelete {
+		t.Fatalf("Expected patience diff to start with a change, got %+v", edits[0])
+	}
+	for _, e := range edits {
+		if e.Op == EditEqual && e.Text == "}" && e.Expected != 2 && e.Expected != 5 {
+			t.Errorf("Unexpected anchor %+v", e)
+		}
+	}
+}
+
+func TestDiffLinesRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randomLines := func() []string {
+		lines := make([]string, rng.Intn(30))
+		for i := range lines {
+			lines[i] = string(rune('a' + rng.Intn(4)))
+		}
+		return lines
+	}
+
+	for iteration := 0; iteration < 500; iteration++ {
+		expected, actual := randomLines(), randomLines()
+		lcs := lcsLength(expected, actual)
+
+		edits := DiffLines(expected, actual, DiffMyers)
+		checkEdits(t, expected, actual, edits)
+		if equal := countOp(edits, EditEqual); equal != lcs {
+			t.Fatalf("Myers kept %d lines, LCS is %d: %q -> %q", equal, lcs, expected, actual)
+		}
+
+		checkEdits(t, expected, actual, DiffLines(expected, actual, DiffPatience))
+	}
+}
+
+// checkEdits verifies that edits reproduce both inputs in order.
+func checkEdits(t *testing.T, expected, actual []string, edits []LineEdit) {
+	t.Helper()
+	var gotExpected, gotActual []string
+	for _, e := range edits {
+		if e.Op != EditInsert {
+			if e.Expected != len(gotExpected) {
+				t.Fatalf("Edit %+v out of order", e)
+			}
+			gotExpected = append(gotExpected, e.Text)
+		}
+		if e.Op != EditDelete {
+			if e.Actual != len(gotActual) {
+				t.Fatalf("Edit %+v out of order", e)
+			}
+			gotActual = append(gotActual, e.Text)
+		}
+	}
+	if strings.Join(gotExpected, "\n") != strings.Join(expected, "\n") ||
+		strings.Join(gotActual, "\n") != strings.Join(actual, "\n") {
+		t.Fatalf("Edits %+v do not reproduce %q -> %q", edits, expected, actual)
+	}
+}
+
+func countOp(edits []LineEdit, op EditOp) int {
+	n := 0
+	for _, e := range edits {
+		if e.Op == op {
+			n++
+		}
+	}
+	return n
+}
+
+func lcsLength(a, b []string) int {
+	table := make([][]int, len(a)+1)
+	for i := range table {
+		table[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				table[i][j] = table[i+1][j+1] + 1
+			} else {
+				table[i][j] = maxInt(table[i+1][j], table[i][j+1])
+			}
+		}
+	}
+	return table[0][0]
+}
@@ -0,0 +1,360 @@
+package tokenizer
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+//
+// Encoding - Input encoding detection and transcoding for streams
+//
+
+// Encoding identifies the character encoding of stream input.
+type Encoding int
+
+const (
+	// EncodingUTF8 is UTF-8, the native representation of all streams.
+	EncodingUTF8 Encoding = iota
+	// EncodingUTF16LE is little-endian UTF-16.
+	EncodingUTF16LE
+	// EncodingUTF16BE is big-endian UTF-16.
+	EncodingUTF16BE
+	// EncodingLatin1 is ISO-8859-1, where every byte maps to the code point of the same value.
+	EncodingLatin1
+	// EncodingAuto detects the encoding from a byte order mark, defaulting to UTF-8.
+	EncodingAuto
+)
+
+// String returns the conventional name of the encoding.
+func (e Encoding) String() string {
+	switch e {
+	case EncodingUTF8:
+		return "UTF-8"
+	case EncodingUTF16LE:
+		return "UTF-16LE"
+	case EncodingUTF16BE:
+		return "UTF-16BE"
+	case EncodingLatin1:
+		return "ISO-8859-1"
+	case EncodingAuto:
+		return "auto"
+	default:
+		return "unknown"
+	}
+}
+
+// EncodedStream is implemented by the built-in streams. It maps stream offsets,
+// which count decoded characters, back to byte offsets in the original input.
+// Token offsets, rows and columns always refer to the decoded text; the
+// Tokenizer records the original byte offset of each token, available from
+// Token.SourceOffset, by calling SourceOffsetAt. Buffered streams only track source offsets when created with
+// NewStreamFromReaderWithEncoding and report -1 otherwise.
+type EncodedStream interface {
+	Stream
+	Encoding() Encoding
+	SourceOffset() int
+	SourceOffsetAt(offset int) int
+}
+
+// DetectEncoding inspects the byte order mark at the start of data.
+// Returns the detected encoding and the length of the BOM in bytes.
+// Input without a recognized BOM is reported as UTF-8 with a BOM length of 0.
+func DetectEncoding(data []byte) (Encoding, int) {
+	switch {
+	case len(data) >= 3 && data[0] == 0xEF && data[1] == 0xBB && data[2] == 0xBF:
+		return EncodingUTF8, 3
+	case len(data) >= 2 && data[0] == 0xFF && data[1] == 0xFE:
+		return EncodingUTF16LE, 2
+	case len(data) >= 2 && data[0] == 0xFE && data[1] == 0xFF:
+		return EncodingUTF16BE, 2
+	default:
+		return EncodingUTF8, 0
+	}
+}
+
+// NewStreamWithEncoding creates an in-memory stream from data in the given encoding.
+// With EncodingAuto the encoding is detected from the BOM. A BOM matching the
+// encoding is skipped. Input is transcoded to UTF-8; malformed sequences become
+// U+FFFD rather than being dropped, so every source character keeps a
+// position in the decoded text.
+//
+// The returned stream implements EncodedStream. Tokens and positions count
+// decoded characters; convert them with SourceOffsetAt to get byte offsets
+// in data.
+func NewStreamWithEncoding(data []byte, encoding Encoding) Stream {
+	encoding, bom := resolveEncoding(data, encoding)
+	source := newSourceMap(encoding, bom)
+	tc := transcoder{encoding: encoding, source: source}
+	_, out := tc.transcode(data[bom:], true, make([]byte, 0, len(data)))
+
+	s := newStreamImpl(string(out))
+	s.source = source
+	return s
+}
+
+// NewStreamFromReaderWithEncoding creates a buffered stream from a reader in the
+// given encoding, transcoding to UTF-8 as data is read. With EncodingAuto the
+// encoding is detected from the BOM. A BOM matching the encoding is skipped.
+//
+// The returned stream implements EncodedStream; as with NewStreamWithEncoding,
+// token offsets count decoded characters and only SourceOffsetAt maps them to
+// bytes read from reader. To do so it records every character whose encoded
+// width differs from the encoding's unit size (non-ASCII for UTF-8, surrogate
+// pairs for UTF-16), so memory grows with the number of such characters.
+// Reset cannot rewind the underlying reader.
+func NewStreamFromReaderWithEncoding(reader io.Reader, encoding Encoding) Stream {
+	buffered := bufio.NewReaderSize(reader, readChunkSize)
+	// Peek errors (including short input) surface through subsequent reads
+	prefix, _ := buffered.Peek(3)
+	encoding, bom := resolveEncoding(prefix, encoding)
+	// nolint:errcheck // Discarding already-peeked bytes cannot fail
+	buffered.Discard(bom)
+
+	source := newSourceMap(encoding, bom)
+	s := newBufferedStreamImpl(&transcodingReader{
+		reader:     buffered,
+		transcoder: transcoder{encoding: encoding, source: source},
+		readBuf:    make([]byte, readChunkSize),
+	})
+	s.source = source
+	return s
+}
+
+// resolveEncoding applies BOM detection for EncodingAuto and returns the BOM
+// length to skip when the BOM matches the effective encoding.
+func resolveEncoding(prefix []byte, encoding Encoding) (Encoding, int) {
+	detected, bom := DetectEncoding(prefix)
+	if encoding == EncodingAuto {
+		return detected, bom
+	}
+	if detected == encoding {
+		return encoding, bom
+	}
+	return encoding, 0
+}
+
+//
+// Source offset mapping
+//
+
+// sourceMap maps decoded character offsets to byte offsets in the original input.
+// Each character is assumed to occupy unit bytes; characters that do not are
+// recorded as exceptions with the cumulative extra width up to and including them.
+type sourceMap struct {
+	encoding Encoding
+	bom      int   // BOM length skipped before the first character
+	unit     int   // Typical encoded width: 1 for UTF-8 and Latin-1, 2 for UTF-16
+	count    int   // Characters recorded so far
+	total    int   // Cumulative extra width of all exceptions
+	runes    []int // Character indices whose width differs from unit (ascending)
+	extra    []int // Cumulative extra width through runes[i]
+}
+
+func newSourceMap(encoding Encoding, bom int) *sourceMap {
+	unit := 1
+	if encoding == EncodingUTF16LE || encoding == EncodingUTF16BE {
+		unit = 2
+	}
+	return &sourceMap{encoding: encoding, bom: bom, unit: unit}
+}
+
+// record registers the next decoded character and its encoded width.
+func (m *sourceMap) record(width int) {
+	if width != m.unit {
+		m.total += width - m.unit
+		m.runes = append(m.runes, m.count)
+		m.extra = append(m.extra, m.total)
+	}
+	m.count++
+}
+
+// offset returns the source byte offset of the character at index cursor.
+func (m *sourceMap) offset(cursor int) int {
+	extra := 0
+	if i := sort.SearchInts(m.runes, cursor); i > 0 {
+		extra = m.extra[i-1]
+	}
+	return m.bom + m.unit*cursor + extra
+}
+
+//
+// Transcoding
+//
+
+// transcoder decodes input in a source encoding to UTF-8, recording each
+// character's encoded width in a sourceMap.
+type transcoder struct {
+	encoding Encoding
+	source   *sourceMap
+}
+
+// transcode decodes as many complete characters from in as possible, appending
+// them to out as UTF-8. Returns the number of input bytes consumed and the
+// extended output. When atEOF is set, a trailing incomplete sequence decodes to U+FFFD.
+func (tc *transcoder) transcode(in []byte, atEOF bool, out []byte) (int, []byte) {
+	i := 0
+	for i < len(in) {
+		r, width := tc.decodeRune(in[i:])
+		if width == 0 {
+			if !atEOF {
+				break
+			}
+			r, width = utf8.RuneError, len(in)-i
+		}
+		out = utf8.AppendRune(out, r)
+		tc.source.record(width)
+		i += width
+	}
+	return i, out
+}
+
+// decodeRune decodes the first character of b.
+// Returns a width of 0 if b holds only an incomplete sequence.
+func (tc *transcoder) decodeRune(b []byte) (rune, int) {
+	switch tc.encoding {
+	case EncodingLatin1:
+		return rune(b[0]), 1
+	case EncodingUTF16LE, EncodingUTF16BE:
+		if len(b) < 2 {
+			return 0, 0
+		}
+		r1 := tc.unit16(b)
+		if !utf16.IsSurrogate(r1) {
+			return r1, 2
+		}
+		if r1 >= 0xDC00 {
+			return utf8.RuneError, 2 // unpaired low surrogate
+		}
+		if len(b) < 4 {
+			return 0, 0
+		}
+		r := utf16.DecodeRune(r1, tc.unit16(b[2:]))
+		if r == utf8.RuneError {
+			return utf8.RuneError, 2 // high surrogate without a low surrogate
+		}
+		return r, 4
+	default:
+		if !utf8.FullRune(b) {
+			return 0, 0
+		}
+		return utf8.DecodeRune(b)
+	}
+}
+
+// unit16 reads one UTF-16 code unit in the transcoder's byte order.
+func (tc *transcoder) unit16(b []byte) rune {
+	if tc.encoding == EncodingUTF16BE {
+		return rune(b[0])<<8 | rune(b[1])
+	}
+	return rune(b[1])<<8 | rune(b[0])
+}
+
+// transcodingReader adapts a reader in a source encoding into a UTF-8 reader.
+// Each Read returns only complete UTF-8 sequences, so the buffered stream never
+// splits a character across refills.
+type transcodingReader struct {
+	reader     io.Reader
+	transcoder transcoder
+	readBuf    []byte // Raw bytes read from reader
+	pending    []byte // Undecoded input carried between reads
+	out        []byte // Decoded UTF-8 not yet returned
+	eof        bool
+	err        error
+}
+
+// Read implements io.Reader.
+func (r *transcodingReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.eof {
+			if r.err != nil {
+				return 0, r.err
+			}
+			return 0, io.EOF
+		}
+
+		n, err := r.reader.Read(r.readBuf)
+		r.pending = append(r.pending, r.readBuf[:n]...)
+		if err != nil {
+			r.eof = true
+			if err != io.EOF {
+				r.err = err
+			}
+		}
+
+		consumed, out := r.transcoder.transcode(r.pending, r.eof, r.out[:0])
+		r.out = out
+		r.pending = append(r.pending[:0], r.pending[consumed:]...)
+	}
+
+	n := len(r.out)
+	if n > len(p) {
+		n = len(p)
+		for n > 0 && !utf8.RuneStart(r.out[n]) {
+			n--
+		}
+	}
+	copy(p, r.out[:n])
+	r.out = r.out[n:]
+	return n, nil
+}
+
+//
+// EncodedStream implementation
+//
+
+// Encoding returns the encoding of the original input.
+func (s *streamImpl) Encoding() Encoding {
+	if s.source == nil {
+		return EncodingUTF8
+	}
+	return s.source.encoding
+}
+
+// SourceOffset returns the byte offset of the current position in the original input.
+func (s *streamImpl) SourceOffset() int {
+	return s.SourceOffsetAt(s.location.Cursor)
+}
+
+// SourceOffsetAt converts a stream offset (as returned by GetOffset or
+// Token.Offset) into a byte offset in the original input.
+// Returns -1 if the offset is outside the stream.
+func (s *streamImpl) SourceOffsetAt(offset int) int {
+	if offset < 0 || offset > s.length {
+		return -1
+	}
+	if s.source != nil {
+		return s.source.offset(offset)
+	}
+	if s.isASCIIOnly {
+		return offset
+	}
+	return s.runeToBytePos[offset]
+}
+
+// Encoding returns the encoding of the original input.
+func (s *bufferedStreamImpl) Encoding() Encoding {
+	if s.source == nil {
+		return EncodingUTF8
+	}
+	return s.source.encoding
+}
+
+// SourceOffset returns the byte offset of the current position in the original input.
+// Returns -1 for streams not created with NewStreamFromReaderWithEncoding.
+func (s *bufferedStreamImpl) SourceOffset() int {
+	return s.SourceOffsetAt(s.location.Cursor)
+}
+
+// SourceOffsetAt converts a stream offset (as returned by GetOffset or
+// Token.Offset) into a byte offset in the original input.
+// Returns -1 if the offset has not been read yet or the stream was not created
+// with NewStreamFromReaderWithEncoding.
+func (s *bufferedStreamImpl) SourceOffsetAt(offset int) int {
+	if s.source == nil || offset < 0 || offset > s.source.count {
+		return -1
+	}
+	return s.source.offset(offset)
+}
@@ -0,0 +1,222 @@
+package tokenizer
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode/utf16"
+)
+
+func encodeUTF16(s string, bigEndian bool, bom bool) []byte {
+	var buf bytes.Buffer
+	units := utf16.Encode([]rune(s))
+	if bom {
+		units = append([]uint16{0xFEFF}, units...)
+	}
+	for _, u := range units {
+		if bigEndian {
+			buf.WriteByte(byte(u >> 8))
+			buf.WriteByte(byte(u))
+		} else {
+			buf.WriteByte(byte(u))
+			buf.WriteByte(byte(u >> 8))
+		}
+	}
+	return buf.Bytes()
+}
+
+func readAll(stream Stream) string {
+	var sb strings.Builder
+	for {
+		r, ok := stream.NextChar()
+		if !ok {
+			break
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		encoding Encoding
+		bom      int
+	}{
+		{name: "UTF-8 BOM", data: []byte{0xEF, 0xBB, 0xBF, 'a'}, encoding: EncodingUTF8, bom: 3},
+		{name: "UTF-16LE BOM", data: []byte{0xFF, 0xFE, 'a', 0}, encoding: EncodingUTF16LE, bom: 2},
+		{name: "UTF-16BE BOM", data: []byte{0xFE, 0xFF, 0, 'a'}, encoding: EncodingUTF16BE, bom: 2},
+		{name: "no BOM", data: []byte("abc"), encoding: EncodingUTF8, bom: 0},
+		{name: "empty", data: nil, encoding: EncodingUTF8, bom: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoding, bom := DetectEncoding(tt.data)
+			if encoding != tt.encoding || bom != tt.bom {
+				t.Errorf("DetectEncoding() = %v, %d, want %v, %d", encoding, bom, tt.encoding, tt.bom)
+			}
+		})
+	}
+}
+
+func TestEncodingString(t *testing.T) {
+	if EncodingUTF16BE.String() != "UTF-16BE" || EncodingLatin1.String() != "ISO-8859-1" || Encoding(99).String() != "unknown" {
+		t.Fatalf("Unexpected encoding names")
+	}
+}
+
+func TestNewStreamWithEncodingTranscodes(t *testing.T) {
+	text := "a€😀\nz"
+
+	tests := []struct {
+		name     string
+		data     []byte
+		encoding Encoding
+		want     string
+	}{
+		{name: "UTF-16LE with BOM", data: encodeUTF16(text, false, true), encoding: EncodingAuto, want: text},
+		{name: "UTF-16BE with BOM", data: encodeUTF16(text, true, true), encoding: EncodingAuto, want: text},
+		{name: "UTF-16LE explicit", data: encodeUTF16(text, false, false), encoding: EncodingUTF16LE, want: text},
+		{name: "UTF-8 BOM", data: append([]byte{0xEF, 0xBB, 0xBF}, text...), encoding: EncodingAuto, want: text},
+		{name: "Latin-1", data: []byte{'c', 0xE9, 0xFC}, encoding: EncodingLatin1, want: "céü"},
+		{name: "invalid UTF-8 becomes U+FFFD", data: []byte{'a', 0xFF, 'b'}, encoding: EncodingUTF8, want: "a�b"},
+		{name: "unpaired surrogate", data: []byte{0x00, 0xDC, 'a', 0}, encoding: EncodingUTF16LE, want: "�a"},
+		{name: "odd trailing byte", data: []byte{'a', 0, 'b'}, encoding: EncodingUTF16LE, want: "a�"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readAll(NewStreamWithEncoding(tt.data, tt.encoding)); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			reader := iotest.OneByteReader(bytes.NewReader(tt.data))
+			if got := readAll(NewStreamFromReaderWithEncoding(reader, tt.encoding)); got != tt.want {
+				t.Errorf("reader: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodedStreamSourceOffsets(t *testing.T) {
+	// Given - BOM (2) + 'a' (2) + '€' (2) + '😀' (4) + '\n' (2) + 'z' (2)
+	data := encodeUTF16("a€😀\nz", false, true)
+	expected := []int{2, 4, 6, 10, 12, 14}
+
+	for _, stream := range []Stream{
+		NewStreamWithEncoding(data, EncodingAuto),
+		NewStreamFromReaderWithEncoding(bytes.NewReader(data), EncodingAuto),
+	} {
+		encoded, ok := stream.(EncodedStream)
+		if !ok {
+			t.Fatalf("Expected %T to implement EncodedStream", stream)
+		}
+		if encoded.Encoding() != EncodingUTF16LE {
+			t.Fatalf("Expected UTF-16LE, got %v", encoded.Encoding())
+		}
+
+		// When / Then
+		for i, want := range expected {
+			if got := encoded.SourceOffset(); got != want {
+				t.Fatalf("%T: SourceOffset at char %d = %d, want %d", stream, i, got, want)
+			}
+			stream.NextChar()
+		}
+		if got := encoded.SourceOffsetAt(3); got != 10 {
+			t.Fatalf("%T: SourceOffsetAt(3) = %d, want 10", stream, got)
+		}
+		if stream.GetRow() != 2 || stream.GetColumn() != 2 {
+			t.Fatalf("%T: expected row 2, column 2, got %d, %d", stream, stream.GetRow(), stream.GetColumn())
+		}
+	}
+}
+
+func TestPlainStreamSourceOffsets(t *testing.T) {
+	stream := NewStream("é!").(EncodedStream)
+
+	if stream.SourceOffsetAt(1) != 2 || stream.SourceOffsetAt(2) != 3 || stream.SourceOffsetAt(3) != -1 {
+		t.Fatalf("Unexpected UTF-8 offsets")
+	}
+
+	buffered := NewStreamFromReader(strings.NewReader("abc")).(EncodedStream)
+	if buffered.SourceOffset() != -1 || buffered.Encoding() != EncodingUTF8 {
+		t.Fatalf("Expected plain buffered stream to report unknown source offsets")
+	}
+}
+
+func TestNewStreamFromReaderWithEncodingLargeInput(t *testing.T) {
+	// Given - enough UTF-16 input to span many reader chunks
+	text := strings.Repeat("ab😀\n", 20000)
+	data := encodeUTF16(text, true, true)
+
+	// When
+	stream := NewStreamFromReaderWithEncoding(bytes.NewReader(data), EncodingAuto)
+	got := readAll(stream)
+
+	// Then
+	if got != text {
+		t.Fatalf("Transcoded text mismatch: got %d runes, want %d", len([]rune(got)), len([]rune(text)))
+	}
+	if offset := stream.(EncodedStream).SourceOffset(); offset != len(data) {
+		t.Fatalf("Expected final source offset %d, got %d", len(data), offset)
+	}
+}
+
+func TestEncodedStreamTokenizes(t *testing.T) {
+	tokenizer := NewTokenizer(alphaMatcher, numericMatcher)
+	tokenizer.InitializeFromStream(NewStreamWithEncoding(encodeUTF16("abc 123", false, true), EncodingAuto))
+
+	actual := tokenizer.TokenizeToString("\n")
+
+	expected := StripMargin(`
+		|[Alpha: "abc"]
+		|[Whitespace: " "]
+		|[Numeric: "123"]
+		|[EOS]
+	`)
+	diff, tdOk := Diff(expected, actual)
+	if !tdOk {
+		t.Fatalf("Tokenization validation error: \n%v", diff)
+	}
+}
+
+func TestTokenSourceOffsets(t *testing.T) {
+	// Given - BOM (2) + "é1" (4) + " " (2) + "22" (4)
+	data := encodeUTF16("é1 22", true, true)
+
+	for _, stream := range []Stream{
+		NewStreamWithEncoding(data, EncodingAuto),
+		NewStreamFromReaderWithEncoding(bytes.NewReader(data), EncodingAuto),
+	} {
+		tokenizer := NewTokenizer(alphaMatcher, numericMatcher, func(s Stream) *Token {
+			if r, ok := s.NextChar(); ok && r == 'é' {
+				return NewToken("E", []rune{r})
+			}
+			return nil
+		})
+		tokenizer.InitializeFromStream(stream)
+
+		// When
+		var offsets, sources []int
+		for {
+			token, ok := tokenizer.NextToken()
+			if !ok {
+				break
+			}
+			offsets = append(offsets, token.Offset())
+			sources = append(sources, token.SourceOffset())
+		}
+
+		// Then
+		if fmt.Sprint(offsets) != "[0 1 2 3]" || fmt.Sprint(sources) != "[2 4 6 8]" {
+			t.Fatalf("%T: got offsets %v, source offsets %v", stream, offsets, sources)
+		}
+	}
+
+	if token := NewToken("T", []rune("x")); token.SourceOffset() != -1 {
+		t.Fatalf("Expected -1 source offset for an unplaced token, got %d", token.SourceOffset())
+	}
+}
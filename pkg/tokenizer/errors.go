@@ -0,0 +1,42 @@
+package tokenizer
+
+import "fmt"
+
+//
+// Errors - Positioned errors reported by the tokenizer
+//
+
+// PositionError is an error tied to a location in the source text.
+// Err holds the underlying cause, if any, and is exposed through Unwrap
+// so that errors.Is and errors.As see through the position.
+type PositionError struct {
+	Message  string
+	Position Position
+	Err      error
+}
+
+// NewPositionError creates a PositionError with a message and no underlying cause.
+func NewPositionError(pos Position, message string) *PositionError {
+	return &PositionError{
+		Message:  message,
+		Position: pos,
+	}
+}
+
+// Error implements the error interface.
+func (e *PositionError) Error() string {
+	message := e.Message
+	if message == "" && e.Err != nil {
+		message = e.Err.Error()
+	}
+	if e.Position.IsValid() {
+		return fmt.Sprintf("error at line %d, column %d: %s",
+			e.Position.Line, e.Position.Column, message)
+	}
+	return message
+}
+
+// Unwrap returns the underlying cause.
+func (e *PositionError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,360 @@
+package tokenizer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+//
+// Escape Sequences - Decoding of string literal contents
+//
+// An EscapeDialect decodes one backslash escape sequence. Unescape applies a
+// dialect to the contents of a string literal, and QuotedStringMatcherFunc
+// lexes a quoted literal, keeping the raw source text as the token value and
+// the decoded contents as its decoded value.
+//
+
+// EscapeDialect decodes the escape sequence at the start of seq, which begins
+// with a backslash. It appends the decoded text to dst and returns the number
+// of bytes of seq consumed. A nil EscapeDialect treats backslashes as ordinary
+// characters.
+type EscapeDialect func(dst []byte, seq string) ([]byte, int, error)
+
+// Built-in escape dialects.
+var (
+	// NoEscapes treats backslashes as ordinary characters
+	NoEscapes EscapeDialect
+
+	// JSONEscapes decodes RFC 8259 escapes: \" \\ \/ \b \f \n \r \t and \uXXXX.
+	// UTF-16 surrogate pairs are combined; unpaired surrogates decode to U+FFFD
+	// as in encoding/json
+	JSONEscapes EscapeDialect = decodeJSONEscape
+
+	// GoEscapes decodes Go string escapes: \a \b \f \n \r \t \v \\ \' \",
+	// \ooo (three octal digits), \xhh, \uhhhh and \Uhhhhhhhh
+	GoEscapes EscapeDialect = decodeGoEscape
+
+	// CEscapes decodes C escapes: \a \b \f \n \r \t \v \\ \' \" \?, one to
+	// three octal digits, \x followed by any number of hex digits, and the
+	// universal character names \uhhhh and \Uhhhhhhhh
+	CEscapes EscapeDialect = decodeCEscape
+)
+
+var (
+	errEscapeIncomplete = errors.New("incomplete escape sequence")
+	errEscapeRange      = errors.New("escape sequence value out of range")
+)
+
+// Unescape decodes the escape sequences in s, the contents of a string literal
+// without its quotes. Errors are *PositionError values locating the invalid
+// sequence, treating s as a single line; like token offsets, the error's
+// offset counts runes.
+//
+// Example:
+//
+//	Unescape(`caf\u00e9\n`, JSONEscapes) -> "café\n", nil
+//	Unescape(`bad \q`, JSONEscapes)       -> error at line 1, column 5: invalid escape sequence \q
+func Unescape(s string, dialect EscapeDialect) (string, error) {
+	decoded, at, err := unescape(s, dialect)
+	if err != nil {
+		offset := utf8.RuneCountInString(s[:at])
+		return ``, &PositionError{Message: err.Error(), Position: NewPosition(offset, 1, offset+1), Err: err}
+	}
+	return decoded, nil
+}
+
+// unescape returns the decoded string, or the byte offset of the invalid
+// escape sequence and the error.
+func unescape(s string, dialect EscapeDialect) (string, int, error) {
+	first := strings.IndexByte(s, '\\')
+	if dialect == nil || first < 0 {
+		return s, 0, nil
+	}
+
+	buf := make([]byte, 0, len(s))
+	buf = append(buf, s[:first]...)
+	for i := first; i < len(s); {
+		if s[i] != '\\' {
+			next := strings.IndexByte(s[i:], '\\')
+			if next < 0 {
+				buf = append(buf, s[i:]...)
+				break
+			}
+			buf = append(buf, s[i:i+next]...)
+			i += next
+			continue
+		}
+		var n int
+		var err error
+		buf, n, err = dialect(buf, s[i:])
+		if err != nil {
+			return ``, i, err
+		}
+		i += n
+	}
+	return string(buf), 0, nil
+}
+
+func decodeJSONEscape(dst []byte, seq string) ([]byte, int, error) {
+	if len(seq) < 2 {
+		return dst, 0, errEscapeIncomplete
+	}
+	switch seq[1] {
+	case '"', '\\', '/':
+		return append(dst, seq[1]), 2, nil
+	case 'b':
+		return append(dst, '\b'), 2, nil
+	case 'f':
+		return append(dst, '\f'), 2, nil
+	case 'n':
+		return append(dst, '\n'), 2, nil
+	case 'r':
+		return append(dst, '\r'), 2, nil
+	case 't':
+		return append(dst, '\t'), 2, nil
+	case 'u':
+		r, ok := parseHex(seq[2:], 4)
+		if !ok {
+			return dst, 0, invalidEscape(seq, 6)
+		}
+		if utf16.IsSurrogate(r) {
+			// A high surrogate must be followed by an escaped low surrogate
+			if len(seq) >= 12 && seq[6] == '\\' && seq[7] == 'u' {
+				if low, ok := parseHex(seq[8:], 4); ok {
+					if combined := utf16.DecodeRune(r, low); combined != utf8.RuneError {
+						return utf8.AppendRune(dst, combined), 12, nil
+					}
+				}
+			}
+			r = utf8.RuneError
+		}
+		return utf8.AppendRune(dst, r), 6, nil
+	}
+	return dst, 0, invalidEscape(seq, 2)
+}
+
+func decodeGoEscape(dst []byte, seq string) ([]byte, int, error) {
+	if len(seq) < 2 {
+		return dst, 0, errEscapeIncomplete
+	}
+	if b, ok := simpleEscape(seq[1]); ok {
+		return append(dst, b), 2, nil
+	}
+	switch c := seq[1]; {
+	case c >= '0' && c <= '7':
+		v, ok := parseOctal(seq[1:], 3, 3)
+		if !ok {
+			return dst, 0, invalidEscape(seq, 4)
+		}
+		if v > 0xFF {
+			return dst, 0, errEscapeRange
+		}
+		return append(dst, byte(v)), 4, nil
+	case c == 'x':
+		v, ok := parseHex(seq[2:], 2)
+		if !ok {
+			return dst, 0, invalidEscape(seq, 4)
+		}
+		return append(dst, byte(v)), 4, nil
+	case c == 'u' || c == 'U':
+		return appendUniversal(dst, seq)
+	}
+	return dst, 0, invalidEscape(seq, 2)
+}
+
+func decodeCEscape(dst []byte, seq string) ([]byte, int, error) {
+	if len(seq) < 2 {
+		return dst, 0, errEscapeIncomplete
+	}
+	if seq[1] == '?' {
+		return append(dst, '?'), 2, nil
+	}
+	if b, ok := simpleEscape(seq[1]); ok {
+		return append(dst, b), 2, nil
+	}
+	switch c := seq[1]; {
+	case c >= '0' && c <= '7':
+		n := 1
+		for n < 3 && 1+n < len(seq) && seq[1+n] >= '0' && seq[1+n] <= '7' {
+			n++
+		}
+		v, _ := parseOctal(seq[1:], n, n)
+		if v > 0xFF {
+			return dst, 0, errEscapeRange
+		}
+		return append(dst, byte(v)), 1 + n, nil
+	case c == 'x':
+		n := 0
+		for 2+n < len(seq) && isHexDigit(seq[2+n]) {
+			n++
+		}
+		if n == 0 {
+			return dst, 0, invalidEscape(seq, 2)
+		}
+		v, ok := parseHex(seq[2:], n)
+		if !ok || v > 0xFF {
+			return dst, 0, errEscapeRange
+		}
+		return append(dst, byte(v)), 2 + n, nil
+	case c == 'u' || c == 'U':
+		return appendUniversal(dst, seq)
+	}
+	return dst, 0, invalidEscape(seq, 2)
+}
+
+// simpleEscape decodes the single-character escapes shared by Go and C.
+func simpleEscape(c byte) (byte, bool) {
+	switch c {
+	case 'a':
+		return '\a', true
+	case 'b':
+		return '\b', true
+	case 'f':
+		return '\f', true
+	case 'n':
+		return '\n', true
+	case 'r':
+		return '\r', true
+	case 't':
+		return '\t', true
+	case 'v':
+		return '\v', true
+	case '\\', '\'', '"':
+		return c, true
+	}
+	return 0, false
+}
+
+// appendUniversal decodes \uhhhh and \Uhhhhhhhh, which must name a valid
+// Unicode scalar value.
+func appendUniversal(dst []byte, seq string) ([]byte, int, error) {
+	digits := 4
+	if seq[1] == 'U' {
+		digits = 8
+	}
+	r, ok := parseHex(seq[2:], digits)
+	if !ok {
+		return dst, 0, invalidEscape(seq, 2+digits)
+	}
+	if !utf8.ValidRune(r) {
+		return dst, 0, errEscapeRange
+	}
+	return utf8.AppendRune(dst, r), 2 + digits, nil
+}
+
+// parseHex parses exactly n hex digits at the start of s.
+func parseHex(s string, n int) (rune, bool) {
+	if len(s) < n || n > 8 {
+		return 0, false
+	}
+	var v rune
+	for i := 0; i < n; i++ {
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9':
+			v = v<<4 | rune(c-'0')
+		case c >= 'a' && c <= 'f':
+			v = v<<4 | rune(c-'a'+10)
+		case c >= 'A' && c <= 'F':
+			v = v<<4 | rune(c-'A'+10)
+		default:
+			return 0, false
+		}
+	}
+	return v, true
+}
+
+// parseOctal parses between min and max octal digits at the start of s.
+func parseOctal(s string, min, max int) (int, bool) {
+	v, n := 0, 0
+	for n < max && n < len(s) && s[n] >= '0' && s[n] <= '7' {
+		v = v<<3 | int(s[n]-'0')
+		n++
+	}
+	return v, n >= min
+}
+
+func isHexDigit(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// invalidEscape reports the escape sequence at the start of seq, showing at
+// most n bytes of it.
+func invalidEscape(seq string, n int) error {
+	if n > len(seq) {
+		n = len(seq)
+	}
+	// Do not cut a multi-byte character in half
+	for n < len(seq) && !utf8.RuneStart(seq[n]) {
+		n++
+	}
+	return fmt.Errorf("invalid escape sequence %s", seq[:n])
+}
+
+// QuotedStringMatcherFunc creates a matcher for string literals delimited by
+// quote, decoding escape sequences with dialect.
+//
+// The token value is the raw literal including its quotes, so the tokenizer
+// advances over the source text; the decoded contents are available from
+// Token.DecodedString. Literals may not span lines. An invalid escape sequence
+// or a missing closing quote still yields a token covering the literal, whose
+// Err method returns a *PositionError pointing at the problem.
+//
+// Example:
+//
+//	matcher := QuotedStringMatcherFunc("String", '"', JSONEscapes)
+//	// `"a\tb"` -> token value `"a\tb"`, DecodedString() "a<TAB>b"
+func QuotedStringMatcherFunc(tokenName string, quote rune, dialect EscapeDialect) Matcher {
+	return func(stream Stream) *Token {
+		offset, row, column := stream.GetOffset(), stream.GetRow(), stream.GetColumn()
+		if r, ok := stream.NextChar(); !ok || r != quote {
+			return nil
+		}
+
+		// Fast path: no escape before the closing quote
+		if bs, ok := stream.(ByteStream); ok && quote == '"' {
+			rest := bs.RemainingBytes()
+			if i := FindEscapeOrQuote(rest); i >= 0 && rest[i] == '"' && FindByte(rest[:i], '\n') < 0 {
+				contents := string(rest[:i])
+				return NewDecodedToken(tokenName, []rune(`"`+contents+`"`), contents)
+			}
+		}
+
+		value := []rune{quote}
+		escaped, closed := false, false
+		for {
+			r, ok := stream.PeekChar()
+			if !ok || r == '\n' {
+				break
+			}
+			stream.NextChar()
+			value = append(value, r)
+			if escaped {
+				escaped = false
+			} else if dialect != nil && r == '\\' {
+				escaped = true
+			} else if r == quote {
+				closed = true
+				break
+			}
+		}
+
+		if !closed {
+			pos := NewPosition(offset, row, column)
+			return NewDecodedToken(tokenName, value, NewPositionError(pos, "unterminated string literal"))
+		}
+
+		contents := string(value[1 : len(value)-1])
+		decoded, at, err := unescape(contents, dialect)
+		if err != nil {
+			// Locate the escape: one column for the opening quote plus the runes before it
+			runes := utf8.RuneCountInString(contents[:at]) + 1
+			pos := NewPosition(offset+runes, row, column+runes)
+			return NewDecodedToken(tokenName, value, &PositionError{Message: err.Error(), Position: pos, Err: err})
+		}
+		return NewDecodedToken(tokenName, value, decoded)
+	}
+}
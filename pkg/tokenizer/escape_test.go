@@ -0,0 +1,189 @@
+package tokenizer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUnescape(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect EscapeDialect
+		input   string
+		want    string
+		wantErr string
+	}{
+		{"no escapes", JSONEscapes, "plain text", "plain text", ""},
+		{"none dialect", NoEscapes, `a\nb`, `a\nb`, ""},
+		{"json simple", JSONEscapes, `a\tb\n\"\\\/`, "a\tb\n\"\\/", ""},
+		{"json unicode", JSONEscapes, `caf\u00e9`, "caf\u00e9", ""},
+		{"json surrogate pair", JSONEscapes, `\ud83d\ude00`, "\U0001F600", ""},
+		{"json lone surrogate", JSONEscapes, `\ud83dx`, "\uFFFDx", ""},
+		{"json invalid", JSONEscapes, `bad \q`, "", "error at line 1, column 5: invalid escape sequence \\q"},
+		{"json short unicode", JSONEscapes, `\u12`, "", "error at line 1, column 1: invalid escape sequence \\u12"},
+		{"json trailing backslash", JSONEscapes, `abc\`, "", "error at line 1, column 4: incomplete escape sequence"},
+		{"json rejects go escape", JSONEscapes, `\x41`, "", "error at line 1, column 1: invalid escape sequence \\x"},
+		{"go simple", GoEscapes, `\a\v\'`, "\a\v'", ""},
+		{"go octal and hex", GoEscapes, `\101\x42`, "AB", ""},
+		{"go raw bytes", GoEscapes, `\xff`, "\xff", ""},
+		{"go big unicode", GoEscapes, `\U0001F600`, "\U0001F600", ""},
+		{"go short octal", GoEscapes, `\12`, "", "error at line 1, column 1: invalid escape sequence \\12"},
+		{"go octal range", GoEscapes, `\400`, "", "error at line 1, column 1: escape sequence value out of range"},
+		{"go surrogate", GoEscapes, `\ud800`, "", "error at line 1, column 1: escape sequence value out of range"},
+		{"c question mark", CEscapes, `\?`, "?", ""},
+		{"c short octal", CEscapes, `\0x\12`, "\x00x\n", ""},
+		{"c long hex", CEscapes, `\x0041`, "A", ""},
+		{"c hex range", CEscapes, `\x100`, "", "error at line 1, column 1: escape sequence value out of range"},
+		{"c empty hex", CEscapes, `\xg`, "", "error at line 1, column 1: invalid escape sequence \\x"},
+		{"column counts runes", CEscapes, `\u00e9\u00e9 \z`, "", "error at line 1, column 14: invalid escape sequence \\z"},
+		{"offset counts runes", JSONEscapes, `né \z`, "", "error at line 1, column 4: invalid escape sequence \\z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Unescape(tt.input, tt.dialect)
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("Unescape(%q) = %q, want error %q", tt.input, got, tt.wantErr)
+				}
+				if err.Error() != tt.wantErr {
+					t.Errorf("Unescape(%q) error = %q, want %q", tt.input, err, tt.wantErr)
+				}
+				var posErr *PositionError
+				if !errors.As(err, &posErr) {
+					t.Fatalf("Expected *PositionError, got %T", err)
+				}
+				if posErr.Position.Offset != posErr.Position.Column-1 {
+					t.Errorf("Offset = %d, want the rune offset %d", posErr.Position.Offset, posErr.Position.Column-1)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unescape(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("Unescape(%q) = %+q, want %+q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuotedStringMatcher(t *testing.T) {
+	tokenizer := NewTokenizer(
+		QuotedStringMatcherFunc("String", '"', JSONEscapes),
+		QuotedStringMatcherFunc("Char", '\'', GoEscapes),
+		CharMatcherFunc("Comma", ','),
+	)
+	tokenizer.Initialize(`"plain","tab\there",'\'','\u00e9'`)
+
+	actual := tokenizer.TokenizeToString("\n")
+	expected := StripMargin(`
+		|[String: "\"plain\""]
+		|[Comma: ","]
+		|[String: "\"tab\\there\""]
+		|[Comma: ","]
+		|[Char: "'\\''"]
+		|[Comma: ","]
+		|[Char: "'\\u00e9'"]
+		|[EOS]`)
+
+	diff, tdOk := Diff(expected, actual)
+	if !tdOk {
+		t.Fatalf("Tokenization validation error: \n%v", diff)
+	}
+}
+
+func TestQuotedStringMatcherDecoded(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		raw     string
+		decoded string
+	}{
+		{"fast path", `"hello" rest`, `"hello"`, "hello"},
+		{"fast path non-ascii", `"größe"`, `"größe"`, "größe"},
+		{"escapes", `"a\"b\\c\u00e9" rest`, `"a\"b\\c\u00e9"`, "a\"b\\c\u00e9"},
+		{"empty", `""`, `""`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := QuotedStringMatcherFunc("String", '"', JSONEscapes)(NewStream(tt.input))
+			if token == nil {
+				t.Fatal("Expected a string token")
+			}
+			if token.Err() != nil {
+				t.Fatalf("Unexpected error: %v", token.Err())
+			}
+			if token.ValueString() != tt.raw {
+				t.Errorf("ValueString() = %q, want %q", token.ValueString(), tt.raw)
+			}
+			if token.DecodedString() != tt.decoded {
+				t.Errorf("DecodedString() = %q, want %q", token.DecodedString(), tt.decoded)
+			}
+		})
+	}
+}
+
+func TestQuotedStringMatcherErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		raw     string
+		wantErr string
+	}{
+		{"invalid escape", "x = \"ok \\q\"", "\"ok \\q\"", "error at line 1, column 9: invalid escape sequence \\q"},
+		{"second line", "\n  \"\u00e9\\z\"", "\"\u00e9\\z\"", "error at line 2, column 5: invalid escape sequence \\z"},
+		{"unterminated", "x = \"open\nnext", "\"open", "error at line 1, column 5: unterminated string literal"},
+		{"unterminated at eos", "x = \"open\\\"", "\"open\\\"", "error at line 1, column 5: unterminated string literal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := NewStream(tt.input)
+			for {
+				if r, ok := stream.PeekChar(); !ok || r == '"' {
+					break
+				}
+				stream.NextChar()
+			}
+			token := QuotedStringMatcherFunc("String", '"', JSONEscapes)(stream)
+			if token == nil {
+				t.Fatal("Expected a string token")
+			}
+			if token.ValueString() != tt.raw {
+				t.Errorf("ValueString() = %q, want %q", token.ValueString(), tt.raw)
+			}
+			var posErr *PositionError
+			if !errors.As(token.Err(), &posErr) {
+				t.Fatalf("Expected *PositionError, got %v", token.Err())
+			}
+			if posErr.Error() != tt.wantErr {
+				t.Errorf("Err() = %q, want %q", posErr.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQuotedStringMatcherNoMatch(t *testing.T) {
+	if token := QuotedStringMatcherFunc("String", '"', JSONEscapes)(NewStream("abc")); token != nil {
+		t.Errorf("Expected nil, got %v", token)
+	}
+	if token := QuotedStringMatcherFunc("String", '"', NoEscapes)(NewStream(`"a\"`)); token == nil || token.DecodedString() != `a\` {
+		t.Errorf("Expected backslash to be literal without a dialect, got %v", token)
+	}
+}
+
+func BenchmarkQuotedStringMatcher(b *testing.B) {
+	matcher := QuotedStringMatcherFunc("String", '"', JSONEscapes)
+	b.Run("plain", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			matcher(NewStream(`"the quick brown fox jumps over the lazy dog"`))
+		}
+	})
+	b.Run("escaped", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			matcher(NewStream(`"the quick\tbrown fox\njumps over \u00e9 the lazy dog"`))
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package tokenizer
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+//
+// Identifiers - Unicode identifier matching following UAX #31
+//
+// XID_Start and XID_Continue are derived from the unicode package's general
+// categories and properties:
+//
+//	ID_Start     = L + Nl + Other_ID_Start - Pattern_Syntax - Pattern_White_Space
+//	ID_Continue  = ID_Start + Mn + Mc + Nd + Pc + Other_ID_Continue - Pattern_Syntax - Pattern_White_Space
+//	XID_Start    = ID_Start minus the few characters whose NFKC form is not an identifier start
+//	XID_Continue = ID_Continue minus the same NFKC exceptions
+//
+
+var (
+	idStartTables    = []*unicode.RangeTable{unicode.L, unicode.Nl, unicode.Other_ID_Start}
+	idContinueTables = []*unicode.RangeTable{unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc, unicode.Other_ID_Continue}
+	idExcludedTables = []*unicode.RangeTable{unicode.Pattern_Syntax, unicode.Pattern_White_Space}
+)
+
+// IsXIDStart reports whether r may start an identifier (Unicode XID_Start).
+func IsXIDStart(r rune) bool {
+	if r < utf8.RuneSelf {
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z'
+	}
+	if !unicode.In(r, idStartTables...) || unicode.In(r, idExcludedTables...) {
+		return false
+	}
+	return !isXIDStartException(r)
+}
+
+// IsXIDContinue reports whether r may continue an identifier (Unicode XID_Continue).
+func IsXIDContinue(r rune) bool {
+	if r < utf8.RuneSelf {
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '_'
+	}
+	if !unicode.In(r, idStartTables...) && !unicode.In(r, idContinueTables...) {
+		return false
+	}
+	if unicode.In(r, idExcludedTables...) {
+		return false
+	}
+	return !isXIDContinueException(r)
+}
+
+// isXIDContinueException reports the ID_Continue characters that are not
+// XID_Continue because their NFKC form is not an identifier continuation.
+func isXIDContinueException(r rune) bool {
+	switch {
+	case r == 0x037A, r == 0x309B, r == 0x309C:
+		return true
+	case 0xFC5E <= r && r <= 0xFC63, r == 0xFDFA, r == 0xFDFB:
+		return true
+	case 0xFE70 <= r && r <= 0xFE7E && r%2 == 0:
+		return true
+	}
+	return false
+}
+
+// isXIDStartException additionally excludes characters that are XID_Continue
+// but whose NFKC form does not start with an identifier start.
+func isXIDStartException(r rune) bool {
+	switch r {
+	case 0x0E33, 0x0EB3, 0xFF9E, 0xFF9F:
+		return true
+	}
+	return isXIDContinueException(r)
+}
+
+// IdentifierOptions configures IdentifierMatcherFunc.
+type IdentifierOptions struct {
+	// ExtraStart lists characters allowed anywhere in an identifier, including
+	// the first position, in addition to XID_Start (e.g. '_' or '$')
+	ExtraStart []rune
+
+	// ExtraContinue lists characters allowed after the first position in
+	// addition to XID_Continue (e.g. '-' for CSS or Lisp style names)
+	ExtraContinue []rune
+
+	// Normalize stores the NFC form of the identifier as the token's decoded
+	// value, so that precomposed and decomposed spellings compare equal
+	Normalize bool
+}
+
+// IdentifierMatcherFunc creates a matcher for Unicode identifiers following
+// UAX #31: one XID_Start character followed by any number of XID_Continue
+// characters, extended by the configured extra characters.
+//
+// The token value is always the raw source text. With Normalize set, the NFC
+// form is available from Token.Decoded and Token.DecodedString.
+//
+// Example:
+//
+//	matcher := IdentifierMatcherFunc("Identifier", IdentifierOptions{
+//	    ExtraStart: []rune{'_', '$'},
+//	    Normalize:  true,
+//	})
+//	// Matches "größe", "名前", "$value" and "_private"
+func IdentifierMatcherFunc(tokenName string, options IdentifierOptions) Matcher {
+	extraStart := append([]rune(nil), options.ExtraStart...)
+	extraContinue := append([]rune(nil), options.ExtraContinue...)
+
+	isStart := func(r rune) bool {
+		return IsXIDStart(r) || containsRune(extraStart, r)
+	}
+	isContinue := func(r rune) bool {
+		return IsXIDContinue(r) || containsRune(extraStart, r) || containsRune(extraContinue, r)
+	}
+
+	return func(stream Stream) *Token {
+		r, ok := stream.NextChar()
+		if !ok || !isStart(r) {
+			return nil
+		}
+		value := []rune{r}
+		for {
+			r, ok := stream.PeekChar()
+			if !ok || !isContinue(r) {
+				break
+			}
+			stream.NextChar()
+			value = append(value, r)
+		}
+
+		if !options.Normalize {
+			return NewToken(tokenName, value)
+		}
+		return NewDecodedToken(tokenName, value, NFC(string(value)))
+	}
+}
+
+func containsRune(runes []rune, r rune) bool {
+	for _, c := range runes {
+		if c == r {
+			return true
+		}
+	}
+	return false
+}
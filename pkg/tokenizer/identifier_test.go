@@ -0,0 +1,101 @@
+package tokenizer
+
+import (
+	"testing"
+)
+
+func TestIsXIDStartAndContinue(t *testing.T) {
+	tests := []struct {
+		r     rune
+		start bool
+		cont  bool
+	}{
+		{'a', true, true},
+		{'Z', true, true},
+		{'_', false, true},
+		{'5', false, true},
+		{'$', false, false},
+		{'-', false, false},
+		{'\u00DF', true, true},
+		{'\u540D', true, true},
+		{'\u30A2', true, true},
+		{'\u216B', true, true},   // Nl: Roman numeral twelve
+		{'\u0308', false, true},  // Mn: combining diaeresis
+		{'\u0663', false, true},  // Nd: Arabic-Indic digit three
+		{'\u2118', true, true},   // Other_ID_Start: script capital P
+		{'\u00B7', false, true},  // Other_ID_Continue: middle dot
+		{'\u2E2F', false, false}, // Lm but Pattern_Syntax: vertical tilde
+		{'\u037A', false, false}, // ID_Start but NFKC form is not an identifier: ypogegrammeni
+		{'\u0E33', false, true},  // XID_Continue only: Thai sara am
+		{'\uFF9E', false, true},  // XID_Continue only: halfwidth voiced mark
+		{'\u3000', false, false}, // Ideographic space
+	}
+
+	for _, tt := range tests {
+		if got := IsXIDStart(tt.r); got != tt.start {
+			t.Errorf("IsXIDStart(%U) = %v, want %v", tt.r, got, tt.start)
+		}
+		if got := IsXIDContinue(tt.r); got != tt.cont {
+			t.Errorf("IsXIDContinue(%U) = %v, want %v", tt.r, got, tt.cont)
+		}
+	}
+}
+
+func TestIdentifierMatcher(t *testing.T) {
+	tokenizer := NewTokenizer(
+		IdentifierMatcherFunc("Identifier", IdentifierOptions{ExtraStart: []rune{'$'}, ExtraContinue: []rune{'-'}}),
+		CharMatcherFunc("Equals", '='),
+	)
+	tokenizer.Initialize("größe = 名前-2 $x9 _no")
+
+	actual := tokenizer.TokenizeToString("\n")
+	expected := StripMargin(`
+		|[Identifier: "größe"]
+		|[Whitespace: " "]
+		|[Equals: "="]
+		|[Whitespace: " "]
+		|[Identifier: "名前-2"]
+		|[Whitespace: " "]
+		|[Identifier: "$x9"]
+		|[Whitespace: " "]
+		|[Stream...]`)
+
+	diff, tdOk := Diff(expected, actual)
+	if !tdOk {
+		t.Fatalf("Tokenization validation error: \n%v", diff)
+	}
+}
+
+func TestIdentifierMatcherNormalize(t *testing.T) {
+	decomposed := "Größe" // o + combining diaeresis
+	tokenizer := NewTokenizer(IdentifierMatcherFunc("Identifier", IdentifierOptions{Normalize: true}))
+	tokenizer.Initialize(decomposed + " x")
+
+	token, ok := tokenizer.NextToken()
+	if !ok {
+		t.Fatal("Expected identifier token")
+	}
+	if token.ValueString() != decomposed {
+		t.Errorf("ValueString() = %+q, want raw %+q", token.ValueString(), decomposed)
+	}
+	if token.DecodedString() != "Gr\u00F6\u00DFe" {
+		t.Errorf("DecodedString() = %+q, want %+q", token.DecodedString(), "Gr\u00F6\u00DFe")
+	}
+
+	// The stream advances over the raw text, not the shorter normalized value
+	tokenizer.NextToken()
+	if token, _ := tokenizer.NextToken(); token == nil || token.ValueString() != "x" {
+		t.Errorf("Expected identifier x after the normalized token, got %v", token)
+	}
+}
+
+func TestIdentifierMatcherWithoutNormalize(t *testing.T) {
+	stream := NewStream("abc")
+	token := IdentifierMatcherFunc("Identifier", IdentifierOptions{})(stream)
+	if token == nil || token.Decoded() != nil {
+		t.Fatalf("Expected token without decoded value, got %v", token)
+	}
+	if token.DecodedString() != "abc" {
+		t.Errorf("DecodedString() = %q, want raw value", token.DecodedString())
+	}
+}
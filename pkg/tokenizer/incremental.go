@@ -0,0 +1,117 @@
+package tokenizer
+
+import "unicode/utf8"
+
+//
+// Incremental Tokenization - Re-lexing only the region affected by an edit
+//
+
+// TextEdit describes a single replacement in the source text.
+// Offset and Deleted are measured in runes, matching Token.Offset.
+type TextEdit struct {
+	Offset   int    // Position of the first replaced rune
+	Deleted  int    // Number of runes removed at Offset
+	Inserted string // Text inserted at Offset
+}
+
+// TokenRange describes which tokens changed during re-tokenization.
+// Tokens [Start, OldEnd) of the previous list were replaced by tokens
+// [Start, NewEnd) of the updated list; everything else was carried over.
+type TokenRange struct {
+	Start  int
+	OldEnd int
+	NewEnd int
+}
+
+// Retokenize updates a token list after an edit without re-lexing the whole input.
+// text is the full source after the edit has been applied, and previous is the
+// token list produced for the source before the edit.
+//
+// Lexing restarts one token before the first token touched by the edit (so an
+// edit can merge with the preceding token) and stops as soon as a new token
+// matches an old token from after the edit at the same shifted offset. The
+// remaining old tokens are reused with their positions adjusted.
+//
+// The tokenizer is re-initialized with text. Retokenize assumes matchers are
+// deterministic and do not depend on input before the token they match.
+func (t *Tokenizer) Retokenize(text string, previous []Token, edit TextEdit) ([]Token, TokenRange) {
+	t.Initialize(text)
+
+	// Find the first token whose end reaches the edit, then step back one
+	start := len(previous)
+	for i, token := range previous {
+		if token.offset+len(token.value) >= edit.Offset {
+			start = i
+			break
+		}
+	}
+	if start > 0 {
+		start--
+	}
+	if start < len(previous) {
+		restart := previous[start]
+		t.stream.SetLocation(Location{Cursor: restart.offset, Row: restart.row, Column: restart.column})
+	} else if len(previous) > 0 {
+		last := previous[len(previous)-1]
+		t.stream.SetLocation(Location{Cursor: last.offset, Row: last.row, Column: last.column})
+		start = len(previous) - 1
+	}
+
+	editEnd := edit.Offset + edit.Deleted
+	insertedEnd := edit.Offset + utf8.RuneCountInString(edit.Inserted)
+	delta := insertedEnd - editEnd
+
+	// Old tokens at or after the end of the deleted region are resync candidates
+	candidate := start
+	for candidate < len(previous) && previous[candidate].offset < editEnd {
+		candidate++
+	}
+
+	tokens := make([]Token, 0, len(previous)+1)
+	tokens = append(tokens, previous[:start]...)
+
+	for {
+		token, ok := t.NextToken()
+		if !ok {
+			return tokens, TokenRange{Start: start, OldEnd: len(previous), NewEnd: len(tokens)}
+		}
+
+		if token.offset >= insertedEnd {
+			for candidate < len(previous) && previous[candidate].offset+delta < token.offset {
+				candidate++
+			}
+			if candidate < len(previous) && sameToken(previous[candidate], *token, delta) {
+				newEnd := len(tokens)
+				tokens = append(tokens, shiftTokens(previous[candidate:], *token)...)
+				return tokens, TokenRange{Start: start, OldEnd: candidate, NewEnd: newEnd}
+			}
+		}
+
+		tokens = append(tokens, *token)
+	}
+}
+
+// sameToken reports whether a freshly lexed token matches an old token shifted by delta.
+func sameToken(old Token, token Token, delta int) bool {
+	return old.offset+delta == token.offset && old.kind == token.kind && RunesMatch(old.value, token.value)
+}
+
+// shiftTokens copies old tokens, moving them so that old[0] lands on anchor's position.
+// Rows shift uniformly; columns shift only for tokens on the same line as old[0].
+func shiftTokens(old []Token, anchor Token) []Token {
+	first := old[0]
+	offsetDelta := anchor.offset - first.offset
+	rowDelta := anchor.row - first.row
+	columnDelta := anchor.column - first.column
+
+	shifted := make([]Token, len(old))
+	for i, token := range old {
+		if token.row == first.row {
+			token.column += columnDelta
+		}
+		token.offset += offsetDelta
+		token.row += rowDelta
+		shifted[i] = token
+	}
+	return shifted
+}
@@ -0,0 +1,95 @@
+package tokenizer
+
+import (
+	"testing"
+)
+
+func applyEdit(text string, edit TextEdit) string {
+	runes := []rune(text)
+	return string(runes[:edit.Offset]) + edit.Inserted + string(runes[edit.Offset+edit.Deleted:])
+}
+
+func tokenizeAll(text string) []Token {
+	tokenizer := NewTokenizer(alphaMatcher, numericMatcher)
+	tokenizer.Initialize(text)
+	tokens, _ := tokenizer.Tokenize()
+	return tokens
+}
+
+func assertSameTokens(t *testing.T, expected, actual []Token) {
+	t.Helper()
+	if len(expected) != len(actual) {
+		t.Fatalf("Expected %d tokens, got %d: %v", len(expected), len(actual), actual)
+	}
+	for i := range expected {
+		e, a := expected[i], actual[i]
+		if e.kind != a.kind || !RunesMatch(e.value, a.value) || e.offset != a.offset || e.row != a.row || e.column != a.column {
+			t.Fatalf("Token %d differs: expected %s@%d:%d:%d, got %s@%d:%d:%d",
+				i, e.String(), e.offset, e.row, e.column, a.String(), a.offset, a.row, a.column)
+		}
+	}
+}
+
+func TestRetokenizeMatchesFullTokenization(t *testing.T) {
+	source := "abc 123\ndef 456 ghi\n789 jkl"
+
+	tests := []struct {
+		name string
+		edit TextEdit
+	}{
+		{name: "insert inside token", edit: TextEdit{Offset: 1, Inserted: "x"}},
+		{name: "append to token", edit: TextEdit{Offset: 3, Inserted: "z"}},
+		{name: "split token", edit: TextEdit{Offset: 5, Inserted: " "}},
+		{name: "merge tokens", edit: TextEdit{Offset: 15, Deleted: 1}},
+		{name: "insert newline", edit: TextEdit{Offset: 10, Inserted: "\n"}},
+		{name: "delete newline", edit: TextEdit{Offset: 7, Deleted: 1, Inserted: " "}},
+		{name: "replace across lines", edit: TextEdit{Offset: 4, Deleted: 8, Inserted: "99\nqq"}},
+		{name: "insert at start", edit: TextEdit{Offset: 0, Inserted: "1 "}},
+		{name: "insert at end", edit: TextEdit{Offset: 27, Inserted: "mn"}},
+		{name: "delete everything", edit: TextEdit{Offset: 0, Deleted: 27}},
+		{name: "insert unmatched", edit: TextEdit{Offset: 8, Inserted: "!"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := tokenizeAll(source)
+			edited := applyEdit(source, tt.edit)
+
+			tokenizer := NewTokenizer(alphaMatcher, numericMatcher)
+			actual, _ := tokenizer.Retokenize(edited, previous, tt.edit)
+
+			assertSameTokens(t, tokenizeAll(edited), actual)
+		})
+	}
+}
+
+func TestRetokenizeChangedRange(t *testing.T) {
+	// Given
+	source := "abc 123 def 456"
+	previous := tokenizeAll(source)
+	edit := TextEdit{Offset: 5, Deleted: 1, Inserted: "9"}
+
+	// When
+	tokenizer := NewTokenizer(alphaMatcher, numericMatcher)
+	tokens, changed := tokenizer.Retokenize(applyEdit(source, edit), previous, edit)
+
+	// Then - restarts at the whitespace before "123" and resyncs at the following whitespace
+	if changed.Start != 1 || changed.OldEnd != 3 || changed.NewEnd != 3 {
+		t.Fatalf("Unexpected changed range: %+v", changed)
+	}
+	if tokens[2].ValueString() != "193" {
+		t.Fatalf("Expected edited token '193', got %s", tokens[2].String())
+	}
+}
+
+func TestRetokenizeFromEmpty(t *testing.T) {
+	edit := TextEdit{Offset: 0, Inserted: "abc 1"}
+
+	tokenizer := NewTokenizer(alphaMatcher, numericMatcher)
+	tokens, changed := tokenizer.Retokenize("abc 1", nil, edit)
+
+	assertSameTokens(t, tokenizeAll("abc 1"), tokens)
+	if changed.Start != 0 || changed.OldEnd != 0 || changed.NewEnd != 3 {
+		t.Fatalf("Unexpected changed range: %+v", changed)
+	}
+}
@@ -0,0 +1,107 @@
+package tokenizer
+
+//
+// Line Terminators - Configurable line-break recognition for position tracking
+//
+
+// LineTerminatorPolicy selects which characters end a line when streams track
+// row and column positions.
+type LineTerminatorPolicy int
+
+const (
+	// LineTerminatorLF treats only LF ('\n') as a line break. This is the default.
+	LineTerminatorLF LineTerminatorPolicy = iota
+	// LineTerminatorCRLF treats LF, CR and the CRLF pair as line breaks,
+	// counting CRLF as a single break. Use this for classic Mac (CR-only) files.
+	LineTerminatorCRLF
+	// LineTerminatorUnicode extends LineTerminatorCRLF with the Unicode line
+	// terminators NEL (U+0085), LINE SEPARATOR (U+2028) and PARAGRAPH SEPARATOR (U+2029).
+	LineTerminatorUnicode
+)
+
+// Unicode line terminators recognized by LineTerminatorUnicode.
+const (
+	nextLine           = '\u0085'
+	lineSeparator      = '\u2028'
+	paragraphSeparator = '\u2029'
+)
+
+// String returns the name of the policy.
+func (p LineTerminatorPolicy) String() string {
+	switch p {
+	case LineTerminatorLF:
+		return "LF"
+	case LineTerminatorCRLF:
+		return "CRLF"
+	case LineTerminatorUnicode:
+		return "Unicode"
+	default:
+		return "unknown"
+	}
+}
+
+// LineTerminatorStream is implemented by the built-in streams and allows the
+// line-terminator policy to be changed. The policy affects row and column
+// tracking only, never the characters returned.
+//
+// Example:
+//
+//	stream := tokenizer.NewStream("a\rb")
+//	stream.(tokenizer.LineTerminatorStream).SetLineTerminatorPolicy(tokenizer.LineTerminatorCRLF)
+type LineTerminatorStream interface {
+	Stream
+	LineTerminatorPolicy() LineTerminatorPolicy
+	SetLineTerminatorPolicy(policy LineTerminatorPolicy)
+}
+
+// endsLine reports whether consuming r ends a line under the policy.
+// r must not be '\n', which always ends a line. For '\r', next/hasNext
+// describe the following character so CRLF is counted once, on the LF.
+func (p LineTerminatorPolicy) endsLine(r rune, next rune, hasNext bool) bool {
+	switch r {
+	case '\r':
+		return p != LineTerminatorLF && (!hasNext || next != '\n')
+	case nextLine, lineSeparator, paragraphSeparator:
+		return p == LineTerminatorUnicode
+	default:
+		return false
+	}
+}
+
+// endsLineAt reports whether the byte at data[pos-1], just consumed, ends a line
+// under the policy. Multi-byte terminators end the line on their final byte.
+// Like endsLine, it must not be called for '\n'.
+func (p LineTerminatorPolicy) endsLineAt(data []byte, pos int) bool {
+	switch b := data[pos-1]; b {
+	case '\r':
+		return p != LineTerminatorLF && (pos >= len(data) || data[pos] != '\n')
+	case 0x85: // NEL is encoded as C2 85
+		return p == LineTerminatorUnicode && pos >= 2 && data[pos-2] == 0xC2
+	case 0xA8, 0xA9: // LS and PS are encoded as E2 80 A8 and E2 80 A9
+		return p == LineTerminatorUnicode && pos >= 3 && data[pos-3] == 0xE2 && data[pos-2] == 0x80
+	default:
+		return false
+	}
+}
+
+// LineTerminatorPolicy returns the stream's line-terminator policy.
+func (s *streamImpl) LineTerminatorPolicy() LineTerminatorPolicy {
+	return s.lines
+}
+
+// SetLineTerminatorPolicy changes how the stream counts rows and columns.
+// Set the policy before reading; positions already reported are not recomputed.
+func (s *streamImpl) SetLineTerminatorPolicy(policy LineTerminatorPolicy) {
+	s.lines = policy
+}
+
+// LineTerminatorPolicy returns the stream's line-terminator policy.
+func (s *bufferedStreamImpl) LineTerminatorPolicy() LineTerminatorPolicy {
+	return s.lines
+}
+
+// SetLineTerminatorPolicy changes how the stream counts rows and columns.
+// Set the policy before reading; positions already reported are not recomputed.
+func (s *bufferedStreamImpl) SetLineTerminatorPolicy(policy LineTerminatorPolicy) {
+	s.lines = policy
+}
@@ -0,0 +1,132 @@
+package tokenizer
+
+import (
+	"strings"
+	"testing"
+)
+
+type rowColumn struct {
+	row    int
+	column int
+}
+
+// positionsAfterEachChar reads the stream rune by rune, recording row/column after each.
+func positionsAfterEachChar(stream Stream) []rowColumn {
+	var positions []rowColumn
+	for {
+		if _, ok := stream.NextChar(); !ok {
+			break
+		}
+		positions = append(positions, rowColumn{stream.GetRow(), stream.GetColumn()})
+	}
+	return positions
+}
+
+func newPolicyStreams(input string, policy LineTerminatorPolicy) map[string]Stream {
+	streams := map[string]Stream{
+		"memory":   NewStream(input),
+		"buffered": NewStreamFromReader(strings.NewReader(input)),
+	}
+	for _, stream := range streams {
+		stream.(LineTerminatorStream).SetLineTerminatorPolicy(policy)
+	}
+	return streams
+}
+
+func TestLineTerminatorPolicies(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		policy LineTerminatorPolicy
+		final  rowColumn
+	}{
+		{name: "LF default ignores CR", input: "a\rb\r\nc", policy: LineTerminatorLF, final: rowColumn{2, 2}},
+		{name: "CRLF counts lone CR", input: "a\rb", policy: LineTerminatorCRLF, final: rowColumn{2, 2}},
+		{name: "CRLF counts pair once", input: "a\r\nb\r\n", policy: LineTerminatorCRLF, final: rowColumn{3, 1}},
+		{name: "CRLF trailing CR", input: "a\r", policy: LineTerminatorCRLF, final: rowColumn{2, 1}},
+		{name: "CRLF ignores Unicode", input: "a\u2028b", policy: LineTerminatorCRLF, final: rowColumn{1, 4}},
+		{name: "Unicode NEL LS PS", input: "a\u0085b\u2028c\u2029d", policy: LineTerminatorUnicode, final: rowColumn{4, 2}},
+		{name: "Unicode mixed", input: "a\r\nb\rc\nd", policy: LineTerminatorUnicode, final: rowColumn{4, 2}},
+	}
+
+	for _, tt := range tests {
+		for kind, stream := range newPolicyStreams(tt.input, tt.policy) {
+			t.Run(tt.name+"/"+kind, func(t *testing.T) {
+				positions := positionsAfterEachChar(stream)
+				final := positions[len(positions)-1]
+				if final != tt.final {
+					t.Errorf("final position = %v, want %v (all: %v)", final, tt.final, positions)
+				}
+			})
+		}
+	}
+}
+
+func TestLineTerminatorCRLFMatchesLF(t *testing.T) {
+	// CRLF files report the same positions under LineTerminatorCRLF as under the LF default
+	input := "ab\r\ncd\r\n\r\ne"
+
+	lf := positionsAfterEachChar(NewStream(input))
+	crlf := positionsAfterEachChar(newPolicyStreams(input, LineTerminatorCRLF)["memory"])
+
+	for i := range lf {
+		if lf[i] != crlf[i] {
+			t.Fatalf("position %d: LF %v, CRLF %v", i, lf[i], crlf[i])
+		}
+	}
+}
+
+func TestLineTerminatorPolicyByteOperations(t *testing.T) {
+	// Given
+	input := "a\rb\u2028c \r\n\r d"
+	stream := NewStream(input).(ByteStream)
+	stream.(LineTerminatorStream).SetLineTerminatorPolicy(LineTerminatorUnicode)
+
+	// When - consume "a\rb\u2028c" byte by byte, then skip whitespace
+	for i := 0; i < len("a\rb\u2028c"); i++ {
+		stream.NextByte()
+	}
+	afterBytes := rowColumn{stream.GetRow(), stream.GetColumn()}
+	stream.SkipWhitespace()
+	afterSkip := rowColumn{stream.GetRow(), stream.GetColumn()}
+
+	// Then
+	if afterBytes.row != 3 {
+		t.Fatalf("Expected row 3 after NextByte, got %v", afterBytes)
+	}
+	if afterSkip != (rowColumn{5, 2}) {
+		t.Fatalf("Expected row 5, column 2 after SkipWhitespace, got %v", afterSkip)
+	}
+}
+
+func TestLineTerminatorPolicySurvivesClone(t *testing.T) {
+	for kind, stream := range newPolicyStreams("a\rb", LineTerminatorCRLF) {
+		clone := stream.Clone()
+		if clone.(LineTerminatorStream).LineTerminatorPolicy() != LineTerminatorCRLF {
+			t.Fatalf("%s: expected clone to keep the policy", kind)
+		}
+		positions := positionsAfterEachChar(clone)
+		if positions[1] != (rowColumn{2, 1}) {
+			t.Fatalf("%s: expected CR to end the line in clone, got %v", kind, positions)
+		}
+	}
+}
+
+func TestLineTerminatorPolicyWithTokenizer(t *testing.T) {
+	stream := NewStream("abc\r123")
+	stream.(LineTerminatorStream).SetLineTerminatorPolicy(LineTerminatorCRLF)
+	tokenizer := NewTokenizer(alphaMatcher, numericMatcher)
+	tokenizer.InitializeFromStream(stream)
+
+	tokens, _ := tokenizer.Tokenize()
+
+	if tokens[2].Row() != 2 || tokens[2].Column() != 1 {
+		t.Fatalf("Expected numeric token at 2:1, got %d:%d", tokens[2].Row(), tokens[2].Column())
+	}
+}
+
+func TestLineTerminatorPolicyString(t *testing.T) {
+	if LineTerminatorUnicode.String() != "Unicode" || LineTerminatorPolicy(9).String() != "unknown" {
+		t.Fatalf("Unexpected policy names")
+	}
+}
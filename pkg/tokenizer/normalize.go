@@ -0,0 +1,33 @@
+package tokenizer
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+//
+// Unicode Normalization - Canonical composition (NFC)
+//
+// Normalization is delegated to golang.org/x/text/unicode/norm. Its tables are
+// selected by Go version to match the unicode package, so NFC agrees with
+// IsXIDStart and IsXIDContinue on which characters exist.
+//
+
+// NFC returns s in Unicode Normalization Form C. Invalid UTF-8 is returned unchanged.
+//
+// Example:
+//
+//	NFC("Grün") -> "Grün"
+//	NFC("が")    -> "が"
+func NFC(s string) string {
+	if !utf8.ValidString(s) {
+		return s
+	}
+	return norm.NFC.String(s)
+}
+
+// IsNFC reports whether s is already in Normalization Form C.
+func IsNFC(s string) bool {
+	return !utf8.ValidString(s) || norm.NFC.IsNormalString(s)
+}
@@ -0,0 +1,72 @@
+package tokenizer
+
+import (
+	"testing"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestNFC(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"ascii", "hello", "hello"},
+		{"already composed", "Gr\u00FC\u00DFe", "Gr\u00FC\u00DFe"},
+		{"compose diaeresis", "Gru\u0308\u00DFe", "Gr\u00FC\u00DFe"},
+		{"kana voiced mark", "\u304B\u3099", "\u304C"},
+		{"hangul lv", "\u1100\u1161", "\uAC00"},
+		{"hangul lvt", "\u1100\u1161\u11A8", "\uAC01"},
+		{"hangul syllable plus trailing jamo", "\uAC00\u11A8", "\uAC01"},
+		{"singleton", "\u212B", "\u00C5"},                       // Angstrom sign
+		{"exclusion", "\u0915\u093C", "\u0915\u093C"},           // Devanagari qa stays decomposed
+		{"reorder", "a\u0301\u0323", "\u1EA1\u0301"},            // Dot below (220) sorts before acute (230)
+		{"blocked", "a\u0301\u0301", "\u00E1\u0301"},            // Second acute is blocked by the first
+		{"multi-level", "s\u0307\u0323", "\u1E69"},              // s + dot below + dot above
+		{"non-starter decomposition", "\u0344", "\u0308\u0301"}, // Combining Greek dialytika tonos
+		{"invalid utf8", "a\xffe\u0301", "a\xffe\u0301"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NFC(tt.input); got != tt.want {
+				t.Errorf("NFC(%+q) = %+q, want %+q", tt.input, got, tt.want)
+			}
+			if got := NFC(NFC(tt.input)); got != NFC(tt.input) {
+				t.Errorf("NFC is not idempotent for %+q", tt.input)
+			}
+		})
+	}
+}
+
+func TestIsNFC(t *testing.T) {
+	if !IsNFC("Grüße") {
+		t.Error("Expected composed text to be NFC")
+	}
+	if IsNFC("Grüße") {
+		t.Error("Expected decomposed text not to be NFC")
+	}
+}
+
+func TestNFCUnicodeVersion(t *testing.T) {
+	// Identifiers are classified with the unicode package; normalizing with
+	// tables of another version would disagree on newly assigned characters
+	if norm.Version != unicode.Version {
+		t.Errorf("norm.Version = %s, want unicode.Version %s", norm.Version, unicode.Version)
+	}
+}
+
+func BenchmarkNFC(b *testing.B) {
+	b.Run("ascii", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			NFC("identifier_name")
+		}
+	})
+	b.Run("decomposed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			NFC("Grüße")
+		}
+	})
+}
@@ -1,6 +1,13 @@
 package tokenizer
 
-import "math"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
 
 //
 // Number Utilities - Functions for numeric operations
@@ -59,3 +66,404 @@ func PowInt(m, n int64) int64 {
 	}
 	return result
 }
+
+//
+// Numeric Literals - Matching and typed conversion of number tokens
+//
+
+// NumberOptions configures NumberMatcherFunc and ParseNumber.
+type NumberOptions struct {
+	// Sign allows a leading '+' or '-' as part of the literal
+	Sign bool
+
+	// Hex, Octal and Binary enable the 0x, 0o and 0b base prefixes
+	Hex    bool
+	Octal  bool
+	Binary bool
+
+	// LegacyOctal treats integers with a leading zero, such as 0755, as octal
+	LegacyOctal bool
+
+	// Underscores allows '_' between digits and after a base prefix (1_000, 0x_FF)
+	Underscores bool
+
+	// Floats allows fractions and exponents, including p exponents for
+	// hexadecimal mantissas when Hex is set (0x1.8p3)
+	Floats bool
+}
+
+// DefaultNumberOptions returns Go literal syntax without a sign.
+func DefaultNumberOptions() NumberOptions {
+	return NumberOptions{
+		Hex:         true,
+		Octal:       true,
+		Binary:      true,
+		LegacyOctal: true,
+		Underscores: true,
+		Floats:      true,
+	}
+}
+
+// NumberMatcherFunc creates a matcher for numeric literals.
+//
+// The token value is the raw literal and the decoded value its converted
+// value: int64 for integers that fit, uint64 for larger non-negative
+// integers, *big.Int beyond that, float64 for floating-point literals and
+// *big.Float when they overflow float64 or a non-zero literal would underflow
+// to 0; ast.LiteralNode supports all of these. A literal must start with a
+// digit (or a sign when enabled); a radix point or exponent is only consumed
+// when a digit follows, so 1..2 and 3em still end the number early. Malformed
+// literals, such as 0x, 1__0 or 09, still yield a token whose Err method
+// returns a *PositionError at the offending character.
+//
+// Example:
+//
+//	matcher := NumberMatcherFunc("Number", DefaultNumberOptions())
+//	// "0x_FF"    -> [Number: "0x_FF"], Decoded() int64(255)
+//	// "1.5e3"    -> [Number: "1.5e3"], Decoded() float64(1500)
+//	// "1 << 70"  -> "1", then "70" as separate tokens
+func NumberMatcherFunc(tokenName string, options NumberOptions) Matcher {
+	return func(stream Stream) *Token {
+		offset, row, column := stream.GetOffset(), stream.GetRow(), stream.GetColumn()
+		scanner := numberScanner{stream: stream, options: options, errAt: -1}
+		if !scanner.scan() {
+			return nil
+		}
+		value, at, err := scanner.convert()
+		if err != nil {
+			pos := NewPosition(offset+at, row, column+at)
+			return NewDecodedToken(tokenName, scanner.value, &PositionError{Message: err.Error(), Position: pos, Err: err})
+		}
+		return NewDecodedToken(tokenName, scanner.value, value)
+	}
+}
+
+// ParseNumber converts a complete numeric literal using the same rules and
+// result types as NumberMatcherFunc. Errors are *PositionError values,
+// treating literal as a single line.
+//
+// Example:
+//
+//	ParseNumber("18446744073709551616", DefaultNumberOptions()) -> *big.Int, nil
+func ParseNumber(literal string, options NumberOptions) (interface{}, error) {
+	scanner := numberScanner{stream: NewStream(literal), options: options, errAt: -1}
+	if !scanner.scan() {
+		return nil, NewPositionError(NewPosition(0, 1, 1), "invalid number literal")
+	}
+	if !scanner.stream.IsEos() {
+		at := len(scanner.value)
+		return nil, NewPositionError(NewPosition(at, 1, at+1), "invalid number literal")
+	}
+	value, at, err := scanner.convert()
+	if err != nil {
+		return nil, &PositionError{Message: err.Error(), Position: NewPosition(at, 1, at+1), Err: err}
+	}
+	return value, nil
+}
+
+var errNumberRange = errors.New("number out of range")
+
+// numberScanner reads a numeric literal from a stream, remembering the first
+// syntax error for convert to report.
+type numberScanner struct {
+	stream  Stream
+	options NumberOptions
+	value   []rune
+
+	sign    int  // Length of the sign, 0 or 1
+	base    int  // 2, 8, 10 or 16
+	prefix  rune // 'x', 'o', 'b', '0' for legacy octal, or 0
+	isFloat bool
+	digits  int // Mantissa digits, excluding the prefix
+	hasExp  bool
+
+	errAt  int // Index in value of the first error, or -1
+	errMsg string
+}
+
+func (s *numberScanner) scan() bool {
+	r, ok := s.stream.PeekChar()
+	if ok && s.options.Sign && (r == '+' || r == '-') {
+		if next := s.lookahead(2); len(next) < 2 || !isDecimalDigit(next[1]) {
+			return false
+		}
+		s.next()
+		s.sign = 1
+		r, ok = s.stream.PeekChar()
+	}
+	if !ok || !isDecimalDigit(r) {
+		return false
+	}
+
+	s.base = 10
+	if r == '0' {
+		s.next()
+		p, _ := s.stream.PeekChar()
+		switch lowerASCII(p) {
+		case 'x':
+			s.setPrefix(s.options.Hex, 16, 'x')
+		case 'o':
+			s.setPrefix(s.options.Octal, 8, 'o')
+		case 'b':
+			s.setPrefix(s.options.Binary, 2, 'b')
+		}
+		if s.prefix == 0 {
+			s.digits = 1
+			if s.options.LegacyOctal {
+				s.base, s.prefix = 8, '0'
+			}
+		}
+	}
+	s.digits += s.scanDigits(s.base)
+
+	// Fraction, only for decimal, hexadecimal and legacy octal mantissas
+	if s.options.Floats && (s.prefix == 0 || s.prefix == 'x' || s.prefix == '0') {
+		if next := s.lookahead(2); len(next) == 2 && next[0] == '.' && isDigitInBase(next[1], s.mantissaBase()) {
+			s.next()
+			s.isFloat = true
+			s.digits += s.scanDigits(s.mantissaBase())
+		}
+	}
+
+	// Exponent: e for decimal mantissas, p for hexadecimal ones
+	if s.options.Floats && s.prefix != 'o' && s.prefix != 'b' {
+		marker := byte('e')
+		if s.base == 16 {
+			marker = 'p'
+		}
+		next := s.lookahead(3)
+		if len(next) >= 2 && lowerASCII(next[0]) == rune(marker) {
+			digitAt := 1
+			if next[1] == '+' || next[1] == '-' {
+				digitAt = 2
+			}
+			if digitAt < len(next) && isDecimalDigit(next[digitAt]) {
+				for i := 0; i < digitAt; i++ {
+					s.next()
+				}
+				s.isFloat, s.hasExp = true, true
+				s.scanDigits(10)
+			}
+		}
+	}
+
+	s.validate()
+	return true
+}
+
+func (s *numberScanner) setPrefix(enabled bool, base int, prefix rune) {
+	if enabled {
+		s.next()
+		s.base, s.prefix = base, prefix
+	}
+}
+
+// mantissaBase is the base of the digits after the radix point; legacy octal
+// floats such as 017.5 are decimal.
+func (s *numberScanner) mantissaBase() int {
+	if s.prefix == '0' {
+		return 10
+	}
+	return s.base
+}
+
+// scanDigits consumes digits and separators, returning the number of digits.
+// Bases up to 10 consume every decimal digit so that 0b102 is one malformed
+// literal rather than two tokens.
+func (s *numberScanner) scanDigits(base int) int {
+	count := 0
+	for {
+		r, ok := s.stream.PeekChar()
+		switch {
+		case !ok:
+			return count
+		case r == '_' && s.options.Underscores:
+		case base <= 10 && isDecimalDigit(r):
+			if int(r-'0') >= base && s.prefix != '0' {
+				s.fail(len(s.value), fmt.Sprintf("invalid digit %q in %s literal", r, baseName(base)))
+			}
+			count++
+		case isDigitInBase(r, base):
+			count++
+		default:
+			return count
+		}
+		s.next()
+	}
+}
+
+func (s *numberScanner) validate() {
+	name := baseName(s.base)
+	switch {
+	case s.prefix != 0 && s.prefix != '0' && s.digits == 0:
+		s.fail(s.sign, name+" literal has no digits")
+	case s.prefix == 'x' && s.isFloat && !s.hasExp:
+		s.fail(s.sign, "hexadecimal mantissa requires a 'p' exponent")
+	case s.prefix == '0' && !s.isFloat:
+		// Legacy octal digits are only checked once the literal is known to be an integer
+		for i, r := range s.value {
+			if r >= '8' && r <= '9' {
+				s.fail(i, fmt.Sprintf("invalid digit %q in octal literal", r))
+				break
+			}
+		}
+	}
+	if i := invalidSeparator(s.value[s.sign:]); i >= 0 {
+		s.fail(s.sign+i, "'_' must separate successive digits")
+	}
+}
+
+// convert returns the typed value, or the index of the error and the error.
+func (s *numberScanner) convert() (interface{}, int, error) {
+	if s.errAt >= 0 {
+		return nil, s.errAt, errors.New(s.errMsg)
+	}
+	text := strings.ReplaceAll(string(s.value), "_", "")
+	if s.isFloat {
+		if s.prefix == '0' {
+			text = strings.TrimLeft(text, "+-0") // Decimal, so drop the leading zeros
+			if text == "" || text[0] == '.' || lowerASCII(rune(text[0])) == 'e' {
+				text = "0" + text
+			}
+			if s.value[0] == '-' {
+				text = "-" + text
+			}
+		}
+		f, err := strconv.ParseFloat(text, 64)
+		if err == nil && (f != 0 || !s.nonZeroMantissa(text)) {
+			return f, 0, nil
+		}
+		// Overflow, or underflow of a non-zero literal to 0: keep the value
+		// with enough precision for the mantissa digits
+		prec := uint(s.digits*4 + 64)
+		if b, _, err := big.ParseFloat(text, 0, prec, big.ToNearestEven); err == nil {
+			return b, 0, nil
+		}
+		return nil, 0, errNumberRange
+	}
+
+	neg := strings.HasPrefix(text, "-")
+	digits := strings.TrimLeft(text, "+-")
+	if s.prefix != 0 && s.prefix != '0' {
+		digits = digits[2:]
+	}
+	u, err := strconv.ParseUint(digits, s.base, 64)
+	switch {
+	case err == nil && !neg && u <= math.MaxInt64:
+		return int64(u), 0, nil
+	case err == nil && !neg:
+		return u, 0, nil
+	case err == nil && u <= 1<<63:
+		return int64(-u), 0, nil // Two's complement negation also covers MinInt64
+	}
+	b, ok := new(big.Int).SetString(digits, s.base)
+	if !ok {
+		return nil, 0, errNumberRange
+	}
+	if neg {
+		b.Neg(b)
+	}
+	return b, 0, nil
+}
+
+// nonZeroMantissa reports whether the float literal text has a non-zero digit
+// before its exponent.
+func (s *numberScanner) nonZeroMantissa(text string) bool {
+	text = strings.TrimLeft(text, "+-")
+	exponent := "eE"
+	if s.prefix == 'x' {
+		text, exponent = text[2:], "pP" // Skip "0x"
+	}
+	if i := strings.IndexAny(text, exponent); i >= 0 {
+		text = text[:i]
+	}
+	return strings.IndexAny(text, "123456789abcdefABCDEF") >= 0
+}
+
+func (s *numberScanner) next() {
+	r, _ := s.stream.NextChar()
+	s.value = append(s.value, r)
+}
+
+// lookahead returns up to n upcoming runes without consuming them.
+func (s *numberScanner) lookahead(n int) []rune {
+	location := s.stream.GetLocation()
+	runes := make([]rune, 0, n)
+	for len(runes) < n {
+		r, ok := s.stream.NextChar()
+		if !ok {
+			break
+		}
+		runes = append(runes, r)
+	}
+	s.stream.SetLocation(location)
+	return runes
+}
+
+func (s *numberScanner) fail(at int, msg string) {
+	if s.errAt < 0 {
+		s.errAt, s.errMsg = at, msg
+	}
+}
+
+// invalidSeparator returns the index of the first '_' that does not sit
+// between two digits (a base prefix counts as a digit), or -1.
+func invalidSeparator(value []rune) int {
+	hex := false
+	i := 0
+	prev := '.' // '0' for a digit, '_' for a separator, '.' for anything else
+	if len(value) >= 2 && value[0] == '0' {
+		if p := lowerASCII(value[1]); p == 'x' || p == 'o' || p == 'b' {
+			hex = p == 'x'
+			prev, i = '0', 2
+		}
+	}
+	for ; i < len(value); i++ {
+		r := value[i]
+		switch {
+		case r == '_':
+			if prev != '0' {
+				return i
+			}
+			prev = '_'
+		case isDecimalDigit(r) || hex && isDigitInBase(r, 16):
+			prev = '0'
+		default:
+			if prev == '_' {
+				return i - 1
+			}
+			prev = '.'
+		}
+	}
+	if prev == '_' {
+		return len(value) - 1
+	}
+	return -1
+}
+
+func isDecimalDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isDigitInBase(r rune, base int) bool {
+	if base <= 10 {
+		return r >= '0' && r < '0'+rune(base)
+	}
+	return isDecimalDigit(r) || lowerASCII(r) >= 'a' && lowerASCII(r) < 'a'+rune(base-10)
+}
+
+func lowerASCII(r rune) rune {
+	return r | 0x20
+}
+
+func baseName(base int) string {
+	switch base {
+	case 2:
+		return "binary"
+	case 8:
+		return "octal"
+	case 16:
+		return "hexadecimal"
+	}
+	return "decimal"
+}
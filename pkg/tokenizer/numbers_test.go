@@ -1,6 +1,9 @@
 package tokenizer
 
 import (
+	"errors"
+	"math"
+	"math/big"
 	"testing"
 )
 
@@ -146,3 +149,170 @@ func TestTwoToPowerOfThreeShouldYieldEight(t *testing.T) {
 		t.Fatalf("Power of %v and %v is expected to yield %v", m, n, m*m*m)
 	}
 }
+
+func TestNumberMatcher(t *testing.T) {
+	tokenizer := NewTokenizer(
+		NumberMatcherFunc("Number", DefaultNumberOptions()),
+		IdentifierMatcherFunc("Identifier", IdentifierOptions{}),
+		CharMatcherFunc("Dot", '.'),
+		CharMatcherFunc("Minus", '-'),
+	)
+	tokenizer.Initialize("42 0x_FF 1.5e-3 1..2 3em 0b101 x-1")
+
+	actual := tokenizer.TokenizeToString("\n")
+	expected := StripMargin(`
+		|[Number: "42"]
+		|[Whitespace: " "]
+		|[Number: "0x_FF"]
+		|[Whitespace: " "]
+		|[Number: "1.5e-3"]
+		|[Whitespace: " "]
+		|[Number: "1"]
+		|[Dot: "."]
+		|[Dot: "."]
+		|[Number: "2"]
+		|[Whitespace: " "]
+		|[Number: "3"]
+		|[Identifier: "em"]
+		|[Whitespace: " "]
+		|[Number: "0b101"]
+		|[Whitespace: " "]
+		|[Identifier: "x"]
+		|[Minus: "-"]
+		|[Number: "1"]
+		|[EOS]`)
+
+	diff, tdOk := Diff(expected, actual)
+	if !tdOk {
+		t.Fatalf("Tokenization validation error: \n%v", diff)
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	signed := DefaultNumberOptions()
+	signed.Sign = true
+
+	tests := []struct {
+		name    string
+		options NumberOptions
+		input   string
+		want    interface{}
+	}{
+		{"decimal", DefaultNumberOptions(), "1_000_000", int64(1000000)},
+		{"hex", DefaultNumberOptions(), "0xDead_Beef", int64(0xDEADBEEF)},
+		{"octal prefix", DefaultNumberOptions(), "0o755", int64(0o755)},
+		{"legacy octal", DefaultNumberOptions(), "0755", int64(0o755)},
+		{"leading zero decimal", NumberOptions{Floats: true}, "0755", int64(755)},
+		{"binary", DefaultNumberOptions(), "0b_1010", int64(10)},
+		{"zero", DefaultNumberOptions(), "0", int64(0)},
+		{"max int64", DefaultNumberOptions(), "9223372036854775807", int64(math.MaxInt64)},
+		{"uint64", DefaultNumberOptions(), "18446744073709551615", uint64(math.MaxUint64)},
+		{"min int64", signed, "-9223372036854775808", int64(math.MinInt64)},
+		{"negative", signed, "-0x10", int64(-16)},
+		{"plus", signed, "+7", int64(7)},
+		{"float", DefaultNumberOptions(), "3.25", 3.25},
+		{"exponent", DefaultNumberOptions(), "1E+3", 1000.0},
+		{"legacy octal float", DefaultNumberOptions(), "0129.5", 129.5},
+		{"legacy octal exponent", DefaultNumberOptions(), "09e1", 90.0},
+		{"hex float", DefaultNumberOptions(), "0x1.8p1", 3.0},
+		{"negative float", signed, "-2.5", -2.5},
+		{"zero with large exponent", DefaultNumberOptions(), "0.0e-400", 0.0},
+		{"subnormal", DefaultNumberOptions(), "5e-324", 5e-324},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseNumber(tt.input, tt.options)
+			if err != nil {
+				t.Fatalf("ParseNumber(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseNumber(%q) = %v (%T), want %v (%T)", tt.input, got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNumberBig(t *testing.T) {
+	signed := DefaultNumberOptions()
+	signed.Sign = true
+
+	got, err := ParseNumber("0x1_0000_0000_0000_0000", DefaultNumberOptions())
+	if b, ok := got.(*big.Int); err != nil || !ok || b.String() != "18446744073709551616" {
+		t.Errorf("Expected *big.Int 2^64, got %v (%T), %v", got, got, err)
+	}
+
+	got, err = ParseNumber("-9223372036854775809", signed)
+	if b, ok := got.(*big.Int); err != nil || !ok || b.String() != "-9223372036854775809" {
+		t.Errorf("Expected negative *big.Int, got %v (%T), %v", got, got, err)
+	}
+
+	got, err = ParseNumber("1.5e400", DefaultNumberOptions())
+	if f, ok := got.(*big.Float); err != nil || !ok || f.Text('g', 3) != "1.5e+400" {
+		t.Errorf("Expected *big.Float 1.5e400, got %v (%T), %v", got, got, err)
+	}
+
+	// Non-zero literals that underflow float64 are not rounded to 0
+	got, err = ParseNumber("-1e-400", signed)
+	if f, ok := got.(*big.Float); err != nil || !ok || f.Text('g', 3) != "-1e-400" {
+		t.Errorf("Expected *big.Float -1e-400, got %v (%T), %v", got, got, err)
+	}
+	got, err = ParseNumber("0x1p-2000", DefaultNumberOptions())
+	if f, ok := got.(*big.Float); err != nil || !ok || f.MantExp(nil) != -1999 {
+		t.Errorf("Expected *big.Float 2^-2000, got %v (%T), %v", got, got, err)
+	}
+}
+
+func TestParseNumberErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		options NumberOptions
+		input   string
+		wantErr string
+	}{
+		{"empty hex", DefaultNumberOptions(), "0x", "error at line 1, column 1: hexadecimal literal has no digits"},
+		{"invalid binary digit", DefaultNumberOptions(), "0b1021", "error at line 1, column 5: invalid digit '2' in binary literal"},
+		{"invalid octal digit", DefaultNumberOptions(), "0o78", "error at line 1, column 4: invalid digit '8' in octal literal"},
+		{"invalid legacy octal digit", DefaultNumberOptions(), "0129", "error at line 1, column 4: invalid digit '9' in octal literal"},
+		{"double underscore", DefaultNumberOptions(), "1__0", "error at line 1, column 3: '_' must separate successive digits"},
+		{"trailing underscore", DefaultNumberOptions(), "10_", "error at line 1, column 3: '_' must separate successive digits"},
+		{"underscore before point", DefaultNumberOptions(), "1_.5", "error at line 1, column 2: '_' must separate successive digits"},
+		{"hex float without exponent", DefaultNumberOptions(), "0x1.8", "error at line 1, column 1: hexadecimal mantissa requires a 'p' exponent"},
+		{"exponent overflow", DefaultNumberOptions(), "1e99999999999", "error at line 1, column 1: number out of range"},
+		{"exponent underflow", DefaultNumberOptions(), "1e-99999999999", "error at line 1, column 1: number out of range"},
+		{"underscores disabled", NumberOptions{}, "1_0", "error at line 1, column 2: invalid number literal"},
+		{"not a number", DefaultNumberOptions(), "x1", "error at line 1, column 1: invalid number literal"},
+		{"sign disabled", DefaultNumberOptions(), "-1", "error at line 1, column 1: invalid number literal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseNumber(tt.input, tt.options)
+			if err == nil {
+				t.Fatalf("ParseNumber(%q) = %v, want error %q", tt.input, got, tt.wantErr)
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("ParseNumber(%q) error = %q, want %q", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNumberMatcherErrorPosition(t *testing.T) {
+	tokenizer := NewTokenizer(NumberMatcherFunc("Number", DefaultNumberOptions()))
+	tokenizer.Initialize("\n  0b12")
+
+	tokenizer.NextToken() // Whitespace
+	token, ok := tokenizer.NextToken()
+	if !ok || token.ValueString() != "0b12" {
+		t.Fatalf("Expected the malformed literal as one token, got %v", token)
+	}
+
+	var posErr *PositionError
+	if !errors.As(token.Err(), &posErr) {
+		t.Fatalf("Expected *PositionError, got %v", token.Err())
+	}
+	if want := "error at line 2, column 6: invalid digit '2' in binary literal"; posErr.Error() != want {
+		t.Errorf("Err() = %q, want %q", posErr.Error(), want)
+	}
+}
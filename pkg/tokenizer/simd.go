@@ -0,0 +1,71 @@
+package tokenizer
+
+// SIMD - Vector scanning kernels with SWAR fallback
+//
+// All exported scanning functions in swar.go reduce to one question: where is
+// the first byte that belongs (or, for whitespace skipping, does not belong)
+// to a small class of bytes? A byteClass describes that class, and a single
+// parameterized kernel per instruction set answers the question for whole
+// vector blocks:
+//
+//   - amd64: SSE2 (16 bytes, always available) and AVX2 (32 bytes, selected
+//     at startup via CPUID), in simd_amd64.s
+//   - arm64: NEON (16 bytes, part of the ARMv8 baseline), in simd_arm64.s
+//
+// Other architectures, and builds with the purego tag, use the SWAR versions.
+
+// simdMinLen is the shortest input handed to the vector kernels. Shorter
+// inputs stay on the SWAR path, where the kernel call would not pay off.
+const simdMinLen = 16
+
+// byteClass is a set of bytes searched for by the vector kernels.
+type byteClass struct {
+	set        [4]byte // Bytes in the class; unused slots repeat an earlier byte
+	ctrlOrHigh bool    // Also match control bytes (< 0x20) and non-ASCII bytes (>= 0x80)
+	negate     bool    // Search for the first byte NOT in the class
+}
+
+// Byte classes used by the exported scanning functions.
+var (
+	whitespaceClass    = byteClass{set: [4]byte{' ', '\t', '\n', '\r'}, negate: true}
+	escapeOrQuoteClass = byteClass{set: [4]byte{'"', '\\', '"', '\\'}}
+	escapeClass        = byteClass{set: [4]byte{'"', '\\', '"', '\\'}, ctrlOrHigh: true}
+)
+
+// newByteClass builds a class from one to four bytes.
+func newByteClass(chars []byte, ctrlOrHigh, negate bool) byteClass {
+	class := byteClass{ctrlOrHigh: ctrlOrHigh, negate: negate}
+	for i := range class.set {
+		class.set[i] = chars[i%len(chars)]
+	}
+	return class
+}
+
+// matches reports whether b is found by a search for the class.
+func (c *byteClass) matches(b byte) bool {
+	in := b == c.set[0] || b == c.set[1] || b == c.set[2] || b == c.set[3] ||
+		(c.ctrlOrHigh && (b < 0x20 || b >= 0x80))
+	return in != c.negate
+}
+
+// packedSet returns the class bytes packed little-endian into a uint32,
+// the form passed to the assembly kernels.
+func (c *byteClass) packedSet() uint32 {
+	return uint32(c.set[0]) | uint32(c.set[1])<<8 | uint32(c.set[2])<<16 | uint32(c.set[3])<<24
+}
+
+// index returns the index of the first byte in data found by a search for the
+// class, or -1 if there is none. The vector kernel covers whole blocks and
+// reports where scalar scanning must resume; the tail is checked byte by byte.
+func (c *byteClass) index(data []byte) int {
+	i := 0
+	if useSIMD && len(data) >= simdMinLen {
+		i = indexClass(data, c)
+	}
+	for ; i < len(data); i++ {
+		if c.matches(data[i]) {
+			return i
+		}
+	}
+	return -1
+}
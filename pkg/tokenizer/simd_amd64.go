@@ -0,0 +1,76 @@
+//go:build amd64 && !purego
+
+package tokenizer
+
+// SSE2 is part of the amd64 baseline, so the vector path is always available.
+// AVX2 is used when both the CPU and the operating system support it.
+var (
+	useSIMD = true
+	useAVX2 = detectAVX2()
+)
+
+// indexClass scans whole 32-byte (AVX2) and 16-byte (SSE2) blocks of data.
+// Returns the index of the first byte found, or the index where scalar
+// scanning must resume if no block contains one. len(data) must be >= 16.
+func indexClass(data []byte, c *byteClass) int {
+	set := c.packedSet()
+
+	// Control and non-ASCII bytes are exactly those below 0x20 as signed int8;
+	// nothing is below -128, which disables the check.
+	var threshold byte = 0x80
+	if c.ctrlOrHigh {
+		threshold = 0x20
+	}
+	var flip byte
+	if c.negate {
+		flip = 0xFF
+	}
+
+	i := 0
+	if useAVX2 && len(data) >= 32 {
+		i = indexClassAVX2(&data[0], len(data), set, threshold, flip)
+		if i < len(data)&^31 {
+			return i
+		}
+	}
+	if len(data)-i >= 16 {
+		i += indexClassSSE2(&data[i], len(data)-i, set, threshold, flip)
+	}
+	return i
+}
+
+// detectAVX2 reports whether AVX2 instructions can be used: the CPU must
+// support AVX2 and the OS must save the YMM registers (OSXSAVE + XCR0).
+func detectAVX2() bool {
+	maxLeaf, _, _, _ := cpuid(0, 0)
+	if maxLeaf < 7 {
+		return false
+	}
+	_, _, ecx1, _ := cpuid(1, 0)
+	const osxsave, avx = 1 << 27, 1 << 28
+	if ecx1&osxsave == 0 || ecx1&avx == 0 {
+		return false
+	}
+	if xcr0, _ := xgetbv(); xcr0&0x6 != 0x6 {
+		return false
+	}
+	_, ebx7, _, _ := cpuid(7, 0)
+	const avx2 = 1 << 5
+	return ebx7&avx2 != 0
+}
+
+// indexClassSSE2 scans 16-byte blocks of p[:n]; see indexClass.
+//
+//go:noescape
+func indexClassSSE2(p *byte, n int, set uint32, threshold byte, flip byte) int
+
+// indexClassAVX2 scans 32-byte blocks of p[:n]; see indexClass.
+//
+//go:noescape
+func indexClassAVX2(p *byte, n int, set uint32, threshold byte, flip byte) int
+
+// cpuid executes the CPUID instruction for the given leaf and subleaf.
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+// xgetbv reads extended control register 0 (XCR0).
+func xgetbv() (eax, edx uint32)
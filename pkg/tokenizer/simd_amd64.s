@@ -0,0 +1,158 @@
+//go:build amd64 && !purego
+
+#include "textflag.h"
+
+// Each kernel matches a byte b when
+//
+//	(b == set[0] || b == set[1] || b == set[2] || b == set[3] || int8(b) < int8(threshold)) != (flip == 0xFF)
+//
+// and returns the index of the first match, or the number of bytes covered by
+// whole blocks if no block contains a match.
+
+// func indexClassSSE2(p *byte, n int, set uint32, threshold byte, flip byte) int
+TEXT ·indexClassSSE2(SB), NOSPLIT, $0-32
+	MOVQ    p+0(FP), SI
+	MOVQ    n+8(FP), CX
+	MOVL    set+16(FP), AX
+	MOVQ    $0x0101010101010101, R8
+
+	// Broadcast each set byte, the threshold and the flip mask to all lanes
+	MOVBQZX AX, R9
+	IMULQ   R8, R9
+	MOVQ    R9, X1
+	PUNPCKLQDQ X1, X1
+	SHRL    $8, AX
+	MOVBQZX AX, R9
+	IMULQ   R8, R9
+	MOVQ    R9, X2
+	PUNPCKLQDQ X2, X2
+	SHRL    $8, AX
+	MOVBQZX AX, R9
+	IMULQ   R8, R9
+	MOVQ    R9, X3
+	PUNPCKLQDQ X3, X3
+	SHRL    $8, AX
+	MOVBQZX AX, R9
+	IMULQ   R8, R9
+	MOVQ    R9, X4
+	PUNPCKLQDQ X4, X4
+	MOVBQZX threshold+20(FP), R9
+	IMULQ   R8, R9
+	MOVQ    R9, X5
+	PUNPCKLQDQ X5, X5
+	MOVBQZX flip+21(FP), R9
+	IMULQ   R8, R9
+	MOVQ    R9, X8
+	PUNPCKLQDQ X8, X8
+
+	XORQ    DI, DI
+
+sse2loop:
+	LEAQ    16(DI), R10
+	CMPQ    R10, CX
+	JA      sse2done
+	MOVOU   (SI)(DI*1), X0
+	MOVO    X0, X6
+	PCMPEQB X1, X6
+	MOVO    X0, X7
+	PCMPEQB X2, X7
+	POR     X7, X6
+	MOVO    X0, X7
+	PCMPEQB X3, X7
+	POR     X7, X6
+	MOVO    X0, X7
+	PCMPEQB X4, X7
+	POR     X7, X6
+	MOVO    X5, X7
+	PCMPGTB X0, X7
+	POR     X7, X6
+	PXOR    X8, X6
+	PMOVMSKB X6, AX
+	TESTL   AX, AX
+	JNZ     sse2found
+	MOVQ    R10, DI
+	JMP     sse2loop
+
+sse2found:
+	BSFL    AX, AX
+	ADDQ    AX, DI
+
+sse2done:
+	MOVQ    DI, ret+24(FP)
+	RET
+
+// func indexClassAVX2(p *byte, n int, set uint32, threshold byte, flip byte) int
+TEXT ·indexClassAVX2(SB), NOSPLIT, $0-32
+	MOVQ    p+0(FP), SI
+	MOVQ    n+8(FP), CX
+	MOVL    set+16(FP), AX
+
+	// Broadcast each set byte, the threshold and the flip mask to all lanes
+	VMOVD   AX, X1
+	VPBROADCASTB X1, Y1
+	SHRL    $8, AX
+	VMOVD   AX, X2
+	VPBROADCASTB X2, Y2
+	SHRL    $8, AX
+	VMOVD   AX, X3
+	VPBROADCASTB X3, Y3
+	SHRL    $8, AX
+	VMOVD   AX, X4
+	VPBROADCASTB X4, Y4
+	MOVBLZX threshold+20(FP), AX
+	VMOVD   AX, X5
+	VPBROADCASTB X5, Y5
+	MOVBLZX flip+21(FP), AX
+	VMOVD   AX, X8
+	VPBROADCASTB X8, Y8
+
+	XORQ    DI, DI
+
+avx2loop:
+	LEAQ    32(DI), R10
+	CMPQ    R10, CX
+	JA      avx2done
+	VMOVDQU (SI)(DI*1), Y0
+	VPCMPEQB Y1, Y0, Y6
+	VPCMPEQB Y2, Y0, Y7
+	VPOR    Y7, Y6, Y6
+	VPCMPEQB Y3, Y0, Y7
+	VPOR    Y7, Y6, Y6
+	VPCMPEQB Y4, Y0, Y7
+	VPOR    Y7, Y6, Y6
+	VPCMPGTB Y0, Y5, Y7
+	VPOR    Y7, Y6, Y6
+	VPXOR   Y8, Y6, Y6
+	VPMOVMSKB Y6, AX
+	TESTL   AX, AX
+	JNZ     avx2found
+	MOVQ    R10, DI
+	JMP     avx2loop
+
+avx2found:
+	BSFL    AX, AX
+	ADDQ    AX, DI
+
+avx2done:
+	VZEROUPPER
+	MOVQ    DI, ret+24(FP)
+	RET
+
+// func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+TEXT ·cpuid(SB), NOSPLIT, $0-24
+	MOVL eaxArg+0(FP), AX
+	MOVL ecxArg+4(FP), CX
+	CPUID
+	MOVL AX, eax+8(FP)
+	MOVL BX, ebx+12(FP)
+	MOVL CX, ecx+16(FP)
+	MOVL DX, edx+20(FP)
+	RET
+
+// func xgetbv() (eax, edx uint32)
+TEXT ·xgetbv(SB), NOSPLIT, $0-8
+	MOVL $0, CX
+	XGETBV
+	MOVL AX, eax+0(FP)
+	MOVL DX, edx+4(FP)
+	RET
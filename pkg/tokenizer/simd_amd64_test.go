@@ -0,0 +1,56 @@
+//go:build amd64 && !purego
+
+package tokenizer
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+// TestSIMDKernelsAgree runs every byte class through both the SSE2 and the
+// AVX2 kernel (when the CPU supports it) and compares against scalar scanning.
+func TestSIMDKernelsAgree(t *testing.T) {
+	classes := []byteClass{
+		whitespaceClass,
+		escapeOrQuoteClass,
+		escapeClass,
+		newByteClass([]byte("{"), false, false),
+		newByteClass([]byte(",:]}"), false, false),
+	}
+
+	kernels := []bool{false}
+	if detectAVX2() {
+		kernels = append(kernels, true)
+	}
+	defer func(saved bool) { useAVX2 = saved }(useAVX2)
+
+	rng := rand.New(rand.NewSource(1))
+	alphabet := []byte(" \t\r\nab\"\\{},:]\x00\x1f\x7f\x80\xff")
+	for iteration := 0; iteration < 2000; iteration++ {
+		data := make([]byte, 16+rng.Intn(100))
+		for i := range data {
+			data[i] = alphabet[rng.Intn(len(alphabet))]
+		}
+		// Bias towards long runs of non-matching bytes to reach later blocks
+		if iteration%2 == 0 {
+			copy(data, bytes.Repeat([]byte{' '}, rng.Intn(len(data))))
+		}
+
+		for _, class := range classes {
+			want := -1
+			for i, b := range data {
+				if class.matches(b) {
+					want = i
+					break
+				}
+			}
+			for _, avx2 := range kernels {
+				useAVX2 = avx2
+				if got := class.index(data); got != want {
+					t.Fatalf("avx2=%v class=%+v data=%q: index = %d, want %d", avx2, class, data, got, want)
+				}
+			}
+		}
+	}
+}
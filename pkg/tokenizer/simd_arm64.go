@@ -0,0 +1,25 @@
+//go:build arm64 && !purego
+
+package tokenizer
+
+// NEON (Advanced SIMD) is part of the ARMv8 baseline, so no detection is needed.
+const useSIMD = true
+
+// indexClass scans whole 16-byte blocks of data.
+// Returns the index of the first byte found, or the index where scalar
+// scanning must resume if no block contains one. len(data) must be >= 16.
+func indexClass(data []byte, c *byteClass) int {
+	var ctrl, flip byte
+	if c.ctrlOrHigh {
+		ctrl = 0xFF
+	}
+	if c.negate {
+		flip = 0xFF
+	}
+	return indexClassNEON(&data[0], len(data), c.packedSet(), ctrl, flip)
+}
+
+// indexClassNEON scans 16-byte blocks of p[:n]; see indexClass.
+//
+//go:noescape
+func indexClassNEON(p *byte, n int, set uint32, ctrl byte, flip byte) int
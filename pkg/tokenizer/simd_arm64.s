@@ -0,0 +1,86 @@
+//go:build arm64 && !purego
+
+#include "textflag.h"
+
+// The kernel matches a byte b when
+//
+//	(b == set[0] || b == set[1] || b == set[2] || b == set[3] ||
+//	 (ctrl == 0xFF && (b < 0x20 || b >= 0x80))) != (flip == 0xFF)
+//
+// and returns the index of the first match, or the number of bytes covered by
+// whole blocks if no block contains a match.
+
+// func indexClassNEON(p *byte, n int, set uint32, ctrl byte, flip byte) int
+TEXT ·indexClassNEON(SB), NOSPLIT, $0-32
+	MOVD  p+0(FP), R0
+	MOVD  n+8(FP), R1
+	MOVWU set+16(FP), R2
+	MOVBU ctrl+20(FP), R3
+	MOVBU flip+21(FP), R4
+
+	// Broadcast each set byte and the masks to all lanes
+	VDUP  R2, V1.B16
+	LSR   $8, R2, R5
+	VDUP  R5, V2.B16
+	LSR   $16, R2, R5
+	VDUP  R5, V3.B16
+	LSR   $24, R2, R5
+	VDUP  R5, V4.B16
+	MOVD  $0xE0, R5
+	VDUP  R5, V5.B16
+	MOVD  $0x80, R5
+	VDUP  R5, V6.B16
+	VDUP  R3, V7.B16
+	VDUP  R4, V8.B16
+	VEOR  V12.B16, V12.B16, V12.B16
+
+	MOVD  $0, R6
+
+loop:
+	ADD   $16, R6, R7
+	CMP   R1, R7
+	BHI   done
+	VLD1.P 16(R0), [V0.B16]
+
+	VCMEQ V0.B16, V1.B16, V9.B16
+	VCMEQ V0.B16, V2.B16, V10.B16
+	VORR  V10.B16, V9.B16, V9.B16
+	VCMEQ V0.B16, V3.B16, V10.B16
+	VORR  V10.B16, V9.B16, V9.B16
+	VCMEQ V0.B16, V4.B16, V10.B16
+	VORR  V10.B16, V9.B16, V9.B16
+
+	// Control bytes have none of the top three bits set; non-ASCII bytes have the top bit
+	VAND  V0.B16, V5.B16, V10.B16
+	VCMEQ V10.B16, V12.B16, V10.B16
+	VCMTST V0.B16, V6.B16, V11.B16
+	VORR  V11.B16, V10.B16, V10.B16
+	VAND  V7.B16, V10.B16, V10.B16
+	VORR  V10.B16, V9.B16, V9.B16
+
+	VEOR  V8.B16, V9.B16, V9.B16
+
+	VMOV  V9.D[0], R8
+	VMOV  V9.D[1], R9
+	ORR   R8, R9, R10
+	CBNZ  R10, found
+	MOVD  R7, R6
+	B     loop
+
+found:
+	// Lanes are little-endian: the first matching byte is the lowest set byte
+	CBZ   R8, upper
+	RBIT  R8, R8
+	CLZ   R8, R8
+	ADD   R8>>3, R6, R6
+	B     done
+
+upper:
+	RBIT  R9, R9
+	CLZ   R9, R9
+	ADD   $8, R6, R6
+	ADD   R9>>3, R6, R6
+
+done:
+	MOVD  R6, ret+24(FP)
+	RET
@@ -0,0 +1,12 @@
+//go:build purego || !(amd64 || arm64)
+
+package tokenizer
+
+// No vector kernels are available; the exported scanning functions use SWAR.
+const useSIMD = false
+
+// indexClass is never called when useSIMD is false; it reports that no
+// bytes were scanned so callers fall back to scalar scanning.
+func indexClass(data []byte, c *byteClass) int {
+	return 0
+}
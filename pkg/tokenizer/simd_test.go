@@ -0,0 +1,207 @@
+package tokenizer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+//
+// Reference implementations - Obviously correct byte-by-byte versions
+//
+
+func naiveSkipWhitespace(data []byte) int {
+	for i, b := range data {
+		if b != ' ' && b != '\t' && b != '\n' && b != '\r' {
+			return i
+		}
+	}
+	return len(data)
+}
+
+func naiveNeedsEscaping(data []byte) bool {
+	for _, c := range data {
+		if c < 0x20 || c == '"' || c == '\\' || c > 0x7F {
+			return true
+		}
+	}
+	return false
+}
+
+// simdSeeds covers block boundaries for 16- and 32-byte kernels.
+func simdSeeds() [][]byte {
+	seeds := [][]byte{
+		nil,
+		[]byte(`hello world`),
+		[]byte(strings.Repeat(" ", 15) + "x"),
+		[]byte(strings.Repeat(" \t\r\n", 8) + `"`),
+		[]byte(strings.Repeat("a", 31) + `\`),
+		[]byte(strings.Repeat("a", 32) + "\x01"),
+		[]byte(strings.Repeat("b", 47) + "é"),
+		[]byte(strings.Repeat("c", 64)),
+		[]byte(strings.Repeat(" ", 70)),
+		[]byte("ablxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx"),
+	}
+	for i := 0; i < 80; i += 7 {
+		seeds = append(seeds, append(bytes.Repeat([]byte{'x'}, i), '"', 0x7F, 0x80, 0x1F, 0x20))
+	}
+	return seeds
+}
+
+func FuzzFindByte(f *testing.F) {
+	for _, seed := range simdSeeds() {
+		f.Add(seed, byte('"'))
+		f.Add(seed, byte(0x80))
+	}
+	f.Fuzz(func(t *testing.T, data []byte, b byte) {
+		want := bytes.IndexByte(data, b)
+		if got := FindByte(data, b); got != want {
+			t.Fatalf("FindByte(%q, %q) = %d, want %d", data, b, got, want)
+		}
+		if got := findByteSWAR(data, b); got != want {
+			t.Fatalf("findByteSWAR(%q, %q) = %d, want %d", data, b, got, want)
+		}
+	})
+}
+
+func FuzzFindAnyByte(f *testing.F) {
+	for _, seed := range simdSeeds() {
+		f.Add(seed, []byte(`la`))
+		f.Add(seed, []byte(`"\{}`))
+		f.Add(seed, []byte(`,:[]{}`))
+	}
+	f.Fuzz(func(t *testing.T, data []byte, chars []byte) {
+		want := bytes.IndexAny(data, string(chars))
+		if !isASCII(chars) {
+			// bytes.IndexAny works on runes; fall back to a byte-level reference
+			want = -1
+			for i, b := range data {
+				if bytes.IndexByte(chars, b) >= 0 {
+					want = i
+					break
+				}
+			}
+		}
+		if got := FindAnyByte(data, chars); got != want {
+			t.Fatalf("FindAnyByte(%q, %q) = %d, want %d", data, chars, got, want)
+		}
+		if len(chars) > 1 {
+			if got := findAnyByteSWAR(data, chars); got != want {
+				t.Fatalf("findAnyByteSWAR(%q, %q) = %d, want %d", data, chars, got, want)
+			}
+		}
+	})
+}
+
+func FuzzSkipWhitespace(f *testing.F) {
+	for _, seed := range simdSeeds() {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		want := naiveSkipWhitespace(data)
+		if got := SkipWhitespace(data); got != want {
+			t.Fatalf("SkipWhitespace(%q) = %d, want %d", data, got, want)
+		}
+		if got := skipWhitespaceSWAR(data); got != want {
+			t.Fatalf("skipWhitespaceSWAR(%q) = %d, want %d", data, got, want)
+		}
+	})
+}
+
+func FuzzNeedsEscaping(f *testing.F) {
+	for _, seed := range simdSeeds() {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		want := naiveNeedsEscaping(data)
+		if got := NeedsEscaping(data); got != want {
+			t.Fatalf("NeedsEscaping(%q) = %v, want %v", data, got, want)
+		}
+		if got := needsEscapingSWAR(data); got != want {
+			t.Fatalf("needsEscapingSWAR(%q) = %v, want %v", data, got, want)
+		}
+	})
+}
+
+func FuzzFindEscapeOrQuote(f *testing.F) {
+	for _, seed := range simdSeeds() {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		want := bytes.IndexAny(data, `"\`)
+		if got := FindEscapeOrQuote(data); got != want {
+			t.Fatalf("FindEscapeOrQuote(%q) = %d, want %d", data, got, want)
+		}
+		if got := findEscapeOrQuoteSWAR(data); got != want {
+			t.Fatalf("findEscapeOrQuoteSWAR(%q) = %d, want %d", data, got, want)
+		}
+	})
+}
+
+func TestFindAnyByteEarliestMatchWithinChunk(t *testing.T) {
+	// 'a' precedes 'l' in the same 8-byte chunk even though 'l' is listed first
+	data := []byte("ablxxxxx")
+	if got := findAnyByteSWAR(data, []byte("la")); got != 0 {
+		t.Fatalf("findAnyByteSWAR() = %d, want 0", got)
+	}
+}
+
+func TestByteClassMatches(t *testing.T) {
+	class := newByteClass([]byte("ab"), true, false)
+	if class.set != [4]byte{'a', 'b', 'a', 'b'} {
+		t.Fatalf("Expected set to repeat chars, got %q", class.set)
+	}
+	for _, b := range []byte{'a', 'b', 0x00, 0x1F, 0x80, 0xFF} {
+		if !class.matches(b) {
+			t.Errorf("Expected %#x to match", b)
+		}
+	}
+	for _, b := range []byte{'c', 0x20, 0x7F} {
+		if class.matches(b) {
+			t.Errorf("Expected %#x not to match", b)
+		}
+	}
+}
+
+func isASCII(data []byte) bool {
+	for _, b := range data {
+		if b >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+func BenchmarkFindEscapeOrQuote(b *testing.B) {
+	data := []byte(strings.Repeat("abcdefgh", 512) + `"`)
+
+	b.Run("dispatch", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			FindEscapeOrQuote(data)
+		}
+	})
+	b.Run("swar", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			findEscapeOrQuoteSWAR(data)
+		}
+	})
+}
+
+func BenchmarkSkipWhitespace(b *testing.B) {
+	data := []byte(strings.Repeat(" \t\r\n", 1024) + "x")
+
+	b.Run("dispatch", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			SkipWhitespace(data)
+		}
+	})
+	b.Run("swar", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			skipWhitespaceSWAR(data)
+		}
+	})
+}
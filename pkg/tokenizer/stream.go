@@ -58,6 +58,11 @@ type ByteStream interface {
 // The stream supports UTF-8 encoding and tracks position (offset, line, column).
 // Returns a ByteStream for access to both rune and byte-level operations.
 func NewStream(str string) Stream {
+	return newStreamImpl(str)
+}
+
+// newStreamImpl builds the in-memory stream returned by NewStream.
+func newStreamImpl(str string) *streamImpl {
 	bytes := []byte(str)
 	runes := []rune(str)
 
@@ -119,8 +124,10 @@ type streamImpl struct {
 	totalSize     int    // Number of bytes
 	bytePos       int    // Current byte position
 	location      Location
-	runeToBytePos []int // Maps rune index -> byte offset for sync
-	isASCIIOnly   bool  // True if stream is pure ASCII
+	runeToBytePos []int                // Maps rune index -> byte offset for sync
+	isASCIIOnly   bool                 // True if stream is pure ASCII
+	source        *sourceMap           // Original-encoding offsets, nil for plain UTF-8 input
+	lines         LineTerminatorPolicy // Characters that end a line for row/column tracking
 }
 
 // Location holds position information within the stream.
@@ -142,6 +149,8 @@ func (s *streamImpl) Clone() Stream {
 		location:      s.location,
 		runeToBytePos: s.runeToBytePos, // Shared mapping (read-only)
 		isASCIIOnly:   s.isASCIIOnly,
+		source:        s.source,
+		lines:         s.lines,
 	}
 }
 
@@ -187,13 +196,21 @@ func (s *streamImpl) NextChar() (rune, bool) {
 		s.bytePos = s.runeToBytePos[s.location.Cursor]
 	}
 
-	if r == '\n' {
+	if r == '\n' || (s.lines != LineTerminatorLF && s.endsLine(r)) {
 		s.location.Row += 1
 		s.location.Column = 1
 	}
 	return r, true
 }
 
+// endsLine reports whether the just-consumed rune r ends a line under the stream's policy.
+func (s *streamImpl) endsLine(r rune) bool {
+	if s.location.Cursor < s.length {
+		return s.lines.endsLine(r, s.data[s.location.Cursor], true)
+	}
+	return s.lines.endsLine(r, 0, false)
+}
+
 // MatchChars attempts to match a rune sequence against the stream.
 // If successful, the stream is advanced. If not, the stream position is unchanged.
 func (s *streamImpl) MatchChars(match []rune) bool {
@@ -307,7 +324,7 @@ func (s *streamImpl) NextByte() (byte, bool) {
 	s.syncRuneCursorFromBytePos()
 
 	// Update location tracking for newlines
-	if b == '\n' {
+	if b == '\n' || (s.lines != LineTerminatorLF && s.lines.endsLineAt(s.bytes, s.bytePos)) {
 		s.location.Row++
 		s.location.Column = 1
 	} else {
@@ -336,7 +353,7 @@ func (s *streamImpl) SkipWhitespace() {
 		}
 		s.bytePos++
 
-		if b == '\n' {
+		if b == '\n' || (b == '\r' && s.lines.endsLineAt(s.bytes, s.bytePos)) {
 			s.location.Row++
 			s.location.Column = 1
 		} else {
@@ -421,10 +438,11 @@ const (
 // This ensures that when any clone or the original stream modifies the buffer
 // (through refilling or discarding), all instances see the updated state.
 type sharedBuffer struct {
-	data  []rune // The actual sliding window buffer
-	start int64  // Global offset where buffer starts
-	eof   bool   // True when reader has reached EOF
-	err   error  // Error from reader, if any
+	data    []rune // The actual sliding window buffer
+	start   int64  // Global offset where buffer starts
+	eof     bool   // True when reader has reached EOF
+	err     error  // Error from reader, if any
+	pending []byte // Incomplete UTF-8 sequence at the end of the last read
 }
 
 // NewStreamFromReader creates a new buffered stream instance from an io.Reader.
@@ -445,6 +463,11 @@ type sharedBuffer struct {
 //
 // For small strings that fit entirely in memory, use NewStream() instead.
 func NewStreamFromReader(reader io.Reader) Stream {
+	return newBufferedStreamImpl(reader)
+}
+
+// newBufferedStreamImpl builds the buffered stream returned by NewStreamFromReader.
+func newBufferedStreamImpl(reader io.Reader) *bufferedStreamImpl {
 	shared := &sharedBuffer{
 		data:  make([]rune, 0, bufferSize),
 		start: 0,
@@ -478,9 +501,11 @@ func NewStreamFromReader(reader io.Reader) Stream {
 type bufferedStreamImpl struct {
 	uuid     uuid.UUID
 	reader   io.Reader
-	shared   *sharedBuffer // Shared buffer state (pointer ensures all clones see updates)
-	readBuf  []byte        // Temporary buffer for reading bytes (not shared)
-	location Location      // Current position in stream (unique per instance)
+	shared   *sharedBuffer        // Shared buffer state (pointer ensures all clones see updates)
+	readBuf  []byte               // Temporary buffer for reading bytes (not shared)
+	location Location             // Current position in stream (unique per instance)
+	source   *sourceMap           // Original-encoding offsets, nil for plain UTF-8 input
+	lines    LineTerminatorPolicy // Characters that end a line for row/column tracking
 }
 
 // refillBuffer reads more data from the reader and appends it to the shared buffer.
@@ -493,42 +518,42 @@ func (s *bufferedStreamImpl) refillBuffer() {
 	// Read bytes from the reader
 	n, err := s.reader.Read(s.readBuf)
 	if err != nil {
-		if err == io.EOF {
-			s.shared.eof = true
-		} else {
+		if err != io.EOF {
 			s.shared.err = err
-			s.shared.eof = true
-			return
 		}
+		s.shared.eof = true
 	}
 
-	if n == 0 {
-		return
-	}
-
-	// Decode bytes to runes and append to shared buffer
+	// Decode bytes to runes and append to shared buffer, starting with any
+	// incomplete sequence left over from the previous read
 	data := s.readBuf[:n]
+	if len(s.shared.pending) > 0 {
+		data = append(s.shared.pending, data...)
+		s.shared.pending = nil
+	}
+	decoded := len(s.shared.data)
 	offset := 0
 
 	for offset < len(data) {
-		r, size := utf8.DecodeRune(data[offset:])
-		if r == utf8.RuneError && size == 1 {
-			// Invalid UTF-8, skip this byte
-			offset++
-			continue
+		if !s.shared.eof && !utf8.FullRune(data[offset:]) {
+			// The sequence continues in the next read
+			s.shared.pending = append([]byte(nil), data[offset:]...)
+			break
 		}
+		// Invalid UTF-8 decodes as utf8.RuneError (U+FFFD), one byte at a time
+		r, size := utf8.DecodeRune(data[offset:])
 		s.shared.data = append(s.shared.data, r)
 		offset += size
-
-		// Check if we've filled the buffer
-		if len(s.shared.data) >= bufferSize {
-			break
-		}
 	}
 
-	// If we didn't consume all bytes and not at EOF, we need to handle
-	// partial UTF-8 sequences at the boundary. For now, we'll just continue
-	// on the next read.
+	// The whole chunk is always decoded, even if that briefly grows the buffer
+	// past bufferSize: stopping early would silently drop the rest of the chunk.
+
+	// A short read may end mid-sequence without completing any rune; read on
+	// so callers that refill once still see the next character.
+	if n > 0 && len(s.shared.data) == decoded && len(s.shared.pending) > 0 {
+		s.refillBuffer()
+	}
 }
 
 // ensureBufferHasData ensures the shared buffer has data available at the current position.
@@ -564,6 +589,8 @@ func (s *bufferedStreamImpl) Clone() Stream {
 		shared:   s.shared,                    // Share the pointer to buffer state
 		readBuf:  make([]byte, readChunkSize), // Each clone needs its own read buffer
 		location: s.location,                  // Clone gets its own copy of position
+		source:   s.source,                    // Shared, grows as the reader is decoded
+		lines:    s.lines,
 	}
 }
 
@@ -638,7 +665,7 @@ func (s *bufferedStreamImpl) NextChar() (rune, bool) {
 	s.location.Cursor += 1
 	s.location.Column += 1
 
-	if r == '\n' {
+	if r == '\n' || (s.lines != LineTerminatorLF && s.endsLine(r)) {
 		s.location.Row += 1
 		s.location.Column = 1
 	}
@@ -646,6 +673,16 @@ func (s *bufferedStreamImpl) NextChar() (rune, bool) {
 	return r, true
 }
 
+// endsLine reports whether the just-consumed rune r ends a line under the stream's policy.
+// Only '\r' needs the following rune, so the buffer is refilled only for CR.
+func (s *bufferedStreamImpl) endsLine(r rune) bool {
+	if r == '\r' {
+		next, ok := s.PeekChar()
+		return s.lines.endsLine(r, next, ok)
+	}
+	return s.lines.endsLine(r, 0, false)
+}
+
 // MatchChars attempts to match a rune sequence against the stream.
 // If successful, the stream is advanced. If not, the stream position is unchanged.
 func (s *bufferedStreamImpl) MatchChars(match []rune) bool {
@@ -715,6 +752,7 @@ func (s *bufferedStreamImpl) Reset() {
 		s.shared.start = 0
 		s.shared.eof = false
 		s.shared.err = nil
+		s.shared.pending = nil
 		s.refillBuffer()
 	}
 	// For non-seekable readers, we can only reset position tracking
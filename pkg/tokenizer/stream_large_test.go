@@ -3,6 +3,7 @@ package tokenizer
 import (
 	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 // TestBufferedStreamCloneAcrossMultipleRefills tests that clones work correctly
@@ -192,3 +193,33 @@ func TestBufferedStreamMatchAfterBufferManagement(t *testing.T) {
 		}
 	}
 }
+
+// TestBufferedStreamMultiByteAcrossReads tests that UTF-8 sequences split
+// between reads are decoded whole.
+func TestBufferedStreamMultiByteAcrossReads(t *testing.T) {
+	// Given - 21000 runes of 2-, 3- and 4-byte UTF-8 read a few bytes at a time
+	text := strings.Repeat("é€😀", 7000)
+
+	// When
+	got := readAll(NewStreamFromReader(iotest.HalfReader(strings.NewReader(text))))
+
+	// Then
+	if got != text {
+		t.Fatalf("Decoded text mismatch: got %d runes, want %d", len([]rune(got)), len([]rune(text)))
+	}
+}
+
+// TestBufferedStreamInvalidUTF8 tests that invalid bytes, including a
+// truncated sequence at EOF, decode as U+FFFD instead of being dropped.
+func TestBufferedStreamInvalidUTF8(t *testing.T) {
+	// Given
+	input := "a\xffb\xe2\x82"
+
+	// When
+	got := readAll(NewStreamFromReader(iotest.OneByteReader(strings.NewReader(input))))
+
+	// Then
+	if want := "a\uFFFDb\uFFFD\uFFFD"; got != want {
+		t.Fatalf("Expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,182 @@
+package tokenizer
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// Structural Index - simdjson-style stage 1 pre-scan
+//
+// BuildStructuralIndex makes one pass over the input, 64 bytes at a time, and
+// records the position of every structural byte that is not inside a string.
+// Each 64-byte block is reduced to bitmaps (quotes, escapes, structural bytes)
+// using SWAR comparisons; escaped quotes and string interiors are then resolved
+// with branch-free bit arithmetic, following the approach of simdjson
+// (Langdale & Lemire, "Parsing Gigabytes of JSON per Second", 2019).
+//
+// Parsers can iterate the resulting positions to jump directly between tokens
+// instead of inspecting every byte.
+//
+// As in simdjson, escape characters are honored outside strings too: a quote
+// or structural byte preceded by an odd run of escapes is never reported.
+
+// StructuralSet configures which bytes are structural and how strings are delimited.
+type StructuralSet struct {
+	Chars  []byte // Structural bytes reported outside strings, e.g. "{}[]:,"
+	Quote  byte   // String delimiter; 0 disables string awareness
+	Escape byte   // Escape character; 0 disables escape handling
+}
+
+// JSONStructural is the structural set for JSON.
+var JSONStructural = StructuralSet{
+	Chars:  []byte("{}[]:,"),
+	Quote:  '"',
+	Escape: '\\',
+}
+
+// StructuralIndex holds the positions of structural bytes found by BuildStructuralIndex.
+type StructuralIndex struct {
+	positions      []int
+	unclosedString bool
+}
+
+// Positions returns the byte offsets of all structural bytes in ascending order.
+// Unescaped quotes, both opening and closing, are included so parsers can find
+// string boundaries; structural bytes inside strings are not.
+func (idx *StructuralIndex) Positions() []int {
+	return idx.positions
+}
+
+// Len returns the number of structural positions.
+func (idx *StructuralIndex) Len() int {
+	return len(idx.positions)
+}
+
+// UnclosedString returns true if the input ended inside a string.
+func (idx *StructuralIndex) UnclosedString() bool {
+	return idx.unclosedString
+}
+
+// BuildStructuralIndex scans data once and returns the positions of the
+// structural bytes described by set.
+//
+// Example:
+//
+//	idx := BuildStructuralIndex([]byte(`{"a,b": [1, 2]}`), JSONStructural)
+//	idx.Positions() -> [0 1 5 6 8 10 13 14]
+func BuildStructuralIndex(data []byte, set StructuralSet) *StructuralIndex {
+	s := structuralScanner{set: set}
+	for _, c := range set.Chars {
+		s.targets = append(s.targets, broadcast(c))
+	}
+
+	idx := &StructuralIndex{positions: make([]int, 0, len(data)/4)}
+
+	i := 0
+	for ; i+64 <= len(data); i += 64 {
+		idx.positions = appendBits(idx.positions, s.block(data[i:i+64]), i)
+	}
+	if i < len(data) {
+		// Pad the final partial block with zero bytes, then drop their bits
+		var tail [64]byte
+		n := copy(tail[:], data[i:])
+		mask := s.block(tail[:]) & (1<<uint(n) - 1)
+		idx.positions = appendBits(idx.positions, mask, i)
+	}
+
+	idx.unclosedString = s.inString != 0
+	return idx
+}
+
+// structuralScanner carries state between 64-byte blocks.
+type structuralScanner struct {
+	set      StructuralSet
+	targets  []uint64 // Structural bytes broadcast to all lanes
+	escaped  uint64   // 1 if the first byte of the next block is escaped
+	inString uint64   // All ones if the next block starts inside a string
+}
+
+// block returns the structural bitmap of one 64-byte block (bit i = byte i).
+func (s *structuralScanner) block(data []byte) uint64 {
+	var quotes, escapes, structural uint64
+	quote, escape := broadcast(s.set.Quote), broadcast(s.set.Escape)
+
+	for c := 0; c < 8; c++ {
+		chunk := binary.LittleEndian.Uint64(data[c*8:])
+		shift := uint(c * 8)
+		if s.set.Quote != 0 {
+			quotes |= gatherHighBits(equalBytes(chunk, quote)) << shift
+		}
+		if s.set.Escape != 0 {
+			escapes |= gatherHighBits(equalBytes(chunk, escape)) << shift
+		}
+		var match uint64
+		for _, target := range s.targets {
+			match |= equalBytes(chunk, target)
+		}
+		structural |= gatherHighBits(match) << shift
+	}
+
+	escaped := s.findEscaped(escapes)
+	structural &^= escaped
+	if s.set.Quote == 0 {
+		return structural
+	}
+
+	quotes &^= escaped
+
+	// Prefix XOR marks bytes from each opening quote up to (excluding) its closing quote
+	inString := prefixXor(quotes) ^ s.inString
+	s.inString = uint64(int64(inString) >> 63)
+
+	return (structural &^ inString) | quotes
+}
+
+// findEscaped returns the bytes escaped by an odd-length run of escape
+// characters, carrying a pending escape across block boundaries.
+func (s *structuralScanner) findEscaped(escapes uint64) uint64 {
+	const evenBits = 0x5555555555555555
+
+	escapes &^= s.escaped
+	followsEscape := escapes<<1 | s.escaped
+	oddSequenceStarts := escapes &^ evenBits &^ followsEscape
+
+	sequencesStartingOnEvenBits, carry := bits.Add64(oddSequenceStarts, escapes, 0)
+	s.escaped = carry
+
+	invertMask := sequencesStartingOnEvenBits << 1
+	return (evenBits ^ invertMask) & followsEscape
+}
+
+// equalBytes sets the high bit of each byte of chunk that equals the byte
+// broadcast in target. Unlike hasZeroByte it has no false positives.
+func equalBytes(chunk, target uint64) uint64 {
+	const low7 = 0x7F7F7F7F7F7F7F7F
+	x := chunk ^ target
+	return ^((x&low7 + low7) | x | low7)
+}
+
+// gatherHighBits packs the high bit of each byte into an 8-bit mask (bit i = byte i).
+func gatherHighBits(x uint64) uint64 {
+	return (x >> 7) * 0x0102040810204080 >> 56
+}
+
+// prefixXor returns a mask where bit i is the XOR of bits 0..i of x.
+func prefixXor(x uint64) uint64 {
+	x ^= x << 1
+	x ^= x << 2
+	x ^= x << 4
+	x ^= x << 8
+	x ^= x << 16
+	x ^= x << 32
+	return x
+}
+
+// appendBits appends base+i for every set bit i of mask.
+func appendBits(positions []int, mask uint64, base int) []int {
+	for mask != 0 {
+		positions = append(positions, base+bits.TrailingZeros64(mask))
+		mask &= mask - 1
+	}
+	return positions
+}
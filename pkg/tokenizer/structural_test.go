@@ -0,0 +1,137 @@
+package tokenizer
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// naiveStructuralIndex walks the input byte by byte, tracking string and escape state.
+func naiveStructuralIndex(data []byte, set StructuralSet) ([]int, bool) {
+	positions := []int{}
+	inString, escaped := false, false
+	for i, b := range data {
+		switch {
+		case escaped:
+			escaped = false
+		case set.Escape != 0 && b == set.Escape:
+			escaped = true
+		case set.Quote != 0 && b == set.Quote:
+			inString = !inString
+			positions = append(positions, i)
+		case !inString && bytes.IndexByte(set.Chars, b) >= 0:
+			positions = append(positions, i)
+		}
+	}
+	return positions, inString
+}
+
+func TestBuildStructuralIndex(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		set   StructuralSet
+		want  []int
+	}{
+		{"empty", "", JSONStructural, []int{}},
+		{"object", `{"a,b": [1, 2]}`, JSONStructural, []int{0, 1, 5, 6, 8, 10, 13, 14}},
+		{"escaped quote", `["a\"]", 1]`, JSONStructural, []int{0, 1, 6, 7, 10}},
+		{"escaped backslash", `["a\\", 1]`, JSONStructural, []int{0, 1, 5, 6, 9}},
+		{"no strings", `a"b,c`, StructuralSet{Chars: []byte(",")}, []int{3}},
+		{"no escapes", `'a\',b`, StructuralSet{Chars: []byte(","), Quote: '\''}, []int{0, 3, 4}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			idx := BuildStructuralIndex([]byte(test.input), test.set)
+			if !reflect.DeepEqual(idx.Positions(), test.want) {
+				t.Errorf("Positions() = %v, want %v", idx.Positions(), test.want)
+			}
+			if idx.Len() != len(test.want) {
+				t.Errorf("Len() = %d, want %d", idx.Len(), len(test.want))
+			}
+		})
+	}
+}
+
+func TestStructuralIndexAcrossBlocks(t *testing.T) {
+	// An escape run ends exactly at a block boundary, escaping the first byte of the next block
+	input := `["` + strings.Repeat("x", 60) + `\` + `\` + `\` + `"` + `,"]`
+	want, _ := naiveStructuralIndex([]byte(input), JSONStructural)
+	idx := BuildStructuralIndex([]byte(input), JSONStructural)
+	if !reflect.DeepEqual(idx.Positions(), want) {
+		t.Errorf("Positions() = %v, want %v", idx.Positions(), want)
+	}
+	if idx.UnclosedString() {
+		t.Error("Expected string to be closed")
+	}
+
+	// A string spanning several blocks hides the structural bytes inside it
+	input = `{"k": "` + strings.Repeat("{,}", 50) + `"}`
+	idx = BuildStructuralIndex([]byte(input), JSONStructural)
+	if want := []int{0, 1, 3, 4, 6, len(input) - 2, len(input) - 1}; !reflect.DeepEqual(idx.Positions(), want) {
+		t.Errorf("Positions() = %v, want %v", idx.Positions(), want)
+	}
+}
+
+func TestStructuralIndexUnclosedString(t *testing.T) {
+	idx := BuildStructuralIndex([]byte(`{"open: 1}`), JSONStructural)
+	if !idx.UnclosedString() {
+		t.Error("Expected unclosed string")
+	}
+	if want := []int{0, 1}; !reflect.DeepEqual(idx.Positions(), want) {
+		t.Errorf("Positions() = %v, want %v", idx.Positions(), want)
+	}
+}
+
+func TestStructuralIndexRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	alphabet := []byte(`{}[]:,"\ a1`)
+	for iteration := 0; iteration < 2000; iteration++ {
+		data := make([]byte, rng.Intn(300))
+		for i := range data {
+			data[i] = alphabet[rng.Intn(len(alphabet))]
+		}
+		want, unclosed := naiveStructuralIndex(data, JSONStructural)
+		idx := BuildStructuralIndex(data, JSONStructural)
+		if !reflect.DeepEqual(idx.Positions(), want) {
+			t.Fatalf("data=%q: Positions() = %v, want %v", data, idx.Positions(), want)
+		}
+		if idx.UnclosedString() != unclosed {
+			t.Fatalf("data=%q: UnclosedString() = %v, want %v", data, idx.UnclosedString(), unclosed)
+		}
+	}
+}
+
+func TestEqualBytes(t *testing.T) {
+	// Bytes just above a match would trip hasZeroByte's borrow; equalBytes must not
+	chunk := uint64(0x0201_0001_2C2D_2C00)
+	if got := gatherHighBits(equalBytes(chunk, broadcast(','))); got != 0b00001010 {
+		t.Errorf("equalBytes(',') = %08b, want 00001010", got)
+	}
+	if got := gatherHighBits(equalBytes(chunk, broadcast(0x00))); got != 0b00100001 {
+		t.Errorf("equalBytes(0) = %08b, want 00100001", got)
+	}
+}
+
+func FuzzStructuralIndex(f *testing.F) {
+	f.Add([]byte(`{"a": [1, "b\"c", {"d": null}]}`))
+	f.Add([]byte(strings.Repeat(`\`, 63) + `"x"`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		want, unclosed := naiveStructuralIndex(data, JSONStructural)
+		idx := BuildStructuralIndex(data, JSONStructural)
+		if !reflect.DeepEqual(idx.Positions(), want) || idx.UnclosedString() != unclosed {
+			t.Fatalf("data=%q: got %v/%v, want %v/%v", data, idx.Positions(), idx.UnclosedString(), want, unclosed)
+		}
+	})
+}
+
+func BenchmarkBuildStructuralIndex(b *testing.B) {
+	data := []byte(strings.Repeat(`{"name": "value \"quoted\"", "list": [1, 2, 3]}, `, 256))
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		BuildStructuralIndex(data, JSONStructural)
+	}
+}
@@ -13,6 +13,10 @@ import (
 // These primitives provide 2-8x speedup over naive byte-by-byte scanning
 // for common parsing operations like finding delimiters, skipping whitespace,
 // and detecting escape sequences.
+//
+// On amd64 and arm64 the exported functions dispatch to vector kernels
+// (see simd.go) for inputs of at least simdMinLen bytes; the SWAR versions
+// remain the portable fallback and are used everywhere with the purego tag.
 
 // SWAR constants for parallel byte processing
 const (
@@ -61,6 +65,15 @@ func hasZeroByte(x uint64) uint64 {
 //	FindByte([]byte(`hello world`), ' ') -> 5
 //	FindByte([]byte(`no match`), 'z') -> -1
 func FindByte(data []byte, b byte) int {
+	if useSIMD && len(data) >= simdMinLen {
+		class := byteClass{set: [4]byte{b, b, b, b}}
+		return class.index(data)
+	}
+	return findByteSWAR(data, b)
+}
+
+// findByteSWAR is the portable SWAR implementation of FindByte.
+func findByteSWAR(data []byte, b byte) int {
 	if len(data) == 0 {
 		return -1
 	}
@@ -99,7 +112,7 @@ func FindByte(data []byte, b byte) int {
 // FindAnyByte searches for the first occurrence of any byte in chars.
 // Returns the index of the first match, or -1 if none found.
 //
-// For small char sets (2-4 bytes), uses SWAR to check all in parallel.
+// For small char sets (2-4 bytes), uses vector kernels or SWAR to check all in parallel.
 // For larger char sets, falls back to scanning.
 //
 // Example:
@@ -116,6 +129,17 @@ func FindAnyByte(data []byte, chars []byte) int {
 		return FindByte(data, chars[0])
 	}
 
+	if useSIMD && len(chars) <= 4 && len(data) >= simdMinLen {
+		class := newByteClass(chars, false, false)
+		return class.index(data)
+	}
+
+	return findAnyByteSWAR(data, chars)
+}
+
+// findAnyByteSWAR is the portable SWAR implementation of FindAnyByte
+// for two or more characters.
+func findAnyByteSWAR(data []byte, chars []byte) int {
 	// For 2-4 characters, use SWAR parallel check
 	if len(chars) <= 4 {
 		targets := make([]uint64, len(chars))
@@ -127,12 +151,14 @@ func FindAnyByte(data []byte, chars []byte) int {
 		for ; i+8 <= len(data); i += 8 {
 			chunk := binary.LittleEndian.Uint64(data[i:])
 
+			// Combine all targets so the earliest match in the chunk wins,
+			// regardless of which target it belongs to
+			var match uint64
 			for _, target := range targets {
-				xor := chunk ^ target
-				match := hasZeroByte(xor)
-				if match != 0 {
-					return i + bits.TrailingZeros64(match)/8
-				}
+				match |= hasZeroByte(chunk ^ target)
+			}
+			if match != 0 {
+				return i + bits.TrailingZeros64(match)/8
 			}
 		}
 
@@ -173,6 +199,17 @@ func FindAnyByte(data []byte, chars []byte) int {
 //	SkipWhitespace([]byte(`   hello`)) -> 3
 //	SkipWhitespace([]byte(`\t\n  data`)) -> 4
 func SkipWhitespace(data []byte) int {
+	if useSIMD && len(data) >= simdMinLen {
+		if i := whitespaceClass.index(data); i >= 0 {
+			return i
+		}
+		return len(data)
+	}
+	return skipWhitespaceSWAR(data)
+}
+
+// skipWhitespaceSWAR is the portable SWAR implementation of SkipWhitespace.
+func skipWhitespaceSWAR(data []byte) int {
 	i := 0
 
 	// SWAR: Process 8 bytes at once
@@ -228,6 +265,14 @@ func SkipWhitespace(data []byte) int {
 //	NeedsEscaping([]byte(`hello"world`)) -> true  (contains quote)
 //	NeedsEscaping([]byte(`line\nbreak`)) -> true  (contains backslash)
 func NeedsEscaping(data []byte) bool {
+	if useSIMD && len(data) >= simdMinLen {
+		return escapeClass.index(data) >= 0
+	}
+	return needsEscapingSWAR(data)
+}
+
+// needsEscapingSWAR is the portable SWAR implementation of NeedsEscaping.
+func needsEscapingSWAR(data []byte) bool {
 	if len(data) == 0 {
 		return false
 	}
@@ -276,6 +321,14 @@ func NeedsEscaping(data []byte) bool {
 //	FindEscapeOrQuote([]byte(`hello"world`)) -> 5  (quote)
 //	FindEscapeOrQuote([]byte(`hello\nworld`)) -> 5  (backslash)
 func FindEscapeOrQuote(data []byte) int {
+	if useSIMD && len(data) >= simdMinLen {
+		return escapeOrQuoteClass.index(data)
+	}
+	return findEscapeOrQuoteSWAR(data)
+}
+
+// findEscapeOrQuoteSWAR is the portable SWAR implementation of FindEscapeOrQuote.
+func findEscapeOrQuoteSWAR(data []byte) int {
 	if len(data) == 0 {
 		return -1
 	}
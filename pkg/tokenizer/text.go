@@ -74,6 +74,8 @@ func StripColumn(s string) string {
 
 // Diff compares two strings and outputs a diff format.
 // Returns the diff string and true if the strings matched.
+// Lines are compared positionally up to the first mismatch; use UnifiedDiff
+// to align inputs with inserted or removed lines.
 func Diff(expected string, actual string) (string, bool) {
 	expectedArr := showTabsArray(strings.Split(expected, "\n"))
 	expectedWidth := len("Expected")
@@ -11,17 +11,26 @@ import (
 
 // Token represents a parsed token with its type, value, and position information.
 type Token struct {
-	kind   string
-	value  []rune
-	offset int
-	row    int
-	column int
+	kind    string
+	value   []rune
+	offset  int
+	row     int
+	column  int
+	source  int // byte offset in the original input, or -1
+	decoded interface{}
 }
 
 // NewToken constructs a new Token with the given kind and value.
-// Position fields (offset, row, column) are initialized to -1.
+// Position fields (offset, row, column, source offset) are initialized to -1.
 func NewToken(kind string, value []rune) *Token {
-	return &Token{kind, value, -1, -1, -1}
+	return &Token{kind, value, -1, -1, -1, -1, nil}
+}
+
+// NewDecodedToken constructs a Token carrying both the raw source text and a
+// decoded value, such as a normalized identifier or an unescaped string.
+// The raw value is what the tokenizer consumes from the stream.
+func NewDecodedToken(kind string, value []rune, decoded interface{}) *Token {
+	return &Token{kind, value, -1, -1, -1, -1, decoded}
 }
 
 // Kind returns the token's type/kind.
@@ -39,11 +48,41 @@ func (t *Token) ValueString() string {
 	return string(t.value)
 }
 
-// Offset returns the token's byte offset in the source.
+// Decoded returns the decoded value attached by the matcher, or nil if the
+// token only carries its raw value. When decoding failed it holds the error;
+// see Err.
+func (t *Token) Decoded() interface{} {
+	return t.decoded
+}
+
+// DecodedString returns the decoded value if it is a string, otherwise the raw value.
+func (t *Token) DecodedString() string {
+	if s, ok := t.decoded.(string); ok {
+		return s
+	}
+	return string(t.value)
+}
+
+// Err returns the error recorded by the matcher when the token's raw text
+// could not be decoded, such as an invalid escape sequence, or nil.
+func (t *Token) Err() error {
+	if err, ok := t.decoded.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Offset returns the token's offset in the source, counted in runes.
 func (t *Token) Offset() int {
 	return t.offset
 }
 
+// SourceOffset returns the token's byte offset in the original input, before
+// any transcoding, or -1 if the stream does not track it. See EncodedStream.
+func (t *Token) SourceOffset() int {
+	return t.source
+}
+
 // Row returns the token's line number (1-indexed).
 func (t *Token) Row() int {
 	return t.row
@@ -72,7 +111,8 @@ type Matcher func(stream Stream) *Token
 type Tokenizer struct {
 	matchers []Matcher
 	stream   Stream
-	marks    []Stream // stack of marked positions for rewinding
+	marks    []Stream  // stack of marked positions for rewinding
+	trace    TraceHook // optional hook reporting each matcher attempt
 }
 
 // NewTokenizer constructs a Tokenizer with the given matchers.
@@ -177,9 +217,12 @@ func (t *Tokenizer) NextToken() (*Token, bool) {
 	// Save location for rewinding on failed matches
 	startLocation := t.stream.GetLocation()
 
-	for _, matcher := range t.matchers {
+	for i, matcher := range t.matchers {
 		// Try the matcher directly on the stream (no cloning!)
 		token := matcher(t.stream)
+		if t.trace != nil {
+			t.traceAttempt(i, token, startLocation, false)
+		}
 		if token != nil {
 			// Match succeeded - but the matcher may have consumed extra characters
 			// to determine where the match ends. We need to position the stream
@@ -191,6 +234,10 @@ func (t *Tokenizer) NextToken() (*Token, bool) {
 				token.offset = offset
 				token.row = row
 				token.column = column
+				token.source = -1
+				if es, ok := t.stream.(EncodedStream); ok {
+					token.source = es.SourceOffsetAt(offset)
+				}
 				return token, true
 			}
 			// This shouldn't happen, but if MatchChars fails, try next matcher
@@ -213,9 +260,12 @@ func (t *Tokenizer) PeekToken() (*Token, bool) {
 	// Save the current location to restore after peeking
 	startLocation := t.stream.GetLocation()
 
-	for _, matcher := range t.matchers {
+	for i, matcher := range t.matchers {
 		// Try the matcher directly on the stream (no cloning!)
 		token := matcher(t.stream)
+		if t.trace != nil {
+			t.traceAttempt(i, token, startLocation, true)
+		}
 		if token != nil {
 			// Match succeeded - restore position (peek doesn't advance) and return
 			t.stream.SetLocation(startLocation)
@@ -0,0 +1,104 @@
+package tokenizer
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+//
+// Tracing - Debug hooks reporting matcher attempts
+//
+
+// TraceEvent describes a single matcher attempt made by NextToken or PeekToken.
+type TraceEvent struct {
+	Matcher     int      // Index of the matcher in the tokenizer's matcher list
+	MatcherName string   // Function name of the matcher, e.g. "tokenizer.WhiteSpaceMatcher"
+	Token       *Token   // Token returned by the matcher, or nil if it did not match
+	Consumed    int      // Runes the matcher read from the stream before returning
+	Position    Position // Stream position where the attempt started
+	Peek        bool     // True if the attempt was made by PeekToken
+}
+
+// Matched returns true if the matcher produced a token.
+func (e TraceEvent) Matched() bool {
+	return e.Token != nil
+}
+
+// String returns a one-line, human-readable description of the attempt.
+func (e TraceEvent) String() string {
+	outcome := "no match"
+	if e.Token != nil {
+		outcome = e.Token.String()
+	}
+	peek := ""
+	if e.Peek {
+		peek = " (peek)"
+	}
+	return fmt.Sprintf("%d:%d %s -> %s, consumed %d%s",
+		e.Position.Line, e.Position.Column, e.MatcherName, outcome, e.Consumed, peek)
+}
+
+// TraceHook receives a TraceEvent for every matcher attempt.
+type TraceHook func(event TraceEvent)
+
+// SetTraceHook installs a hook that is called after each matcher attempt.
+// Pass nil to disable tracing. Tracing adds no overhead when disabled.
+func (t *Tokenizer) SetTraceHook(hook TraceHook) {
+	t.trace = hook
+}
+
+// TraceToString tokenizes input and returns a human-readable trace listing
+// every matcher attempt, one per line, followed by the end-of-stream marker
+// used by TokenizeToString. Any previously installed trace hook is restored.
+//
+// Example output:
+//
+//	1:1 tokenizer.WhiteSpaceMatcher -> no match, consumed 0
+//	1:1 main.identifierMatcher -> [Identifier: "x"], consumed 2
+//	[EOS]
+func (t *Tokenizer) TraceToString(input string) string {
+	var sb strings.Builder
+	previous := t.trace
+	t.SetTraceHook(func(event TraceEvent) {
+		sb.WriteString(event.String())
+		sb.WriteString("\n")
+	})
+	defer t.SetTraceHook(previous)
+
+	t.Initialize(input)
+	if _, eos := t.Tokenize(); eos {
+		sb.WriteString(`[EOS]`)
+	} else {
+		sb.WriteString(`[Stream...]`)
+	}
+	return sb.String()
+}
+
+// traceAttempt reports a matcher attempt to the trace hook.
+// It must be called before the stream is rewound to start.
+func (t *Tokenizer) traceAttempt(index int, token *Token, start Location, peek bool) {
+	t.trace(TraceEvent{
+		Matcher:     index,
+		MatcherName: matcherName(t.matchers[index]),
+		Token:       token,
+		Consumed:    t.stream.GetOffset() - start.Cursor,
+		Position:    NewPosition(start.Cursor, start.Row, start.Column),
+		Peek:        peek,
+	})
+}
+
+// matcherName returns the short function name of a matcher,
+// trimming the import path (e.g. "tokenizer.CharMatcherFunc.func1").
+func matcherName(matcher Matcher) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(matcher).Pointer())
+	if fn == nil {
+		return "<unknown matcher>"
+	}
+	name := fn.Name()
+	if idx := strings.LastIndex(name, "/"); idx >= 0 {
+		name = name[idx+1:]
+	}
+	return name
+}
@@ -0,0 +1,79 @@
+package tokenizer
+
+import (
+	"testing"
+)
+
+func TestTraceHookReportsEachAttempt(t *testing.T) {
+	// Given
+	tokenizer := NewTokenizer(alphaMatcher, numericMatcher)
+	tokenizer.Initialize("12")
+	var events []TraceEvent
+	tokenizer.SetTraceHook(func(event TraceEvent) {
+		events = append(events, event)
+	})
+
+	// When
+	tokenizer.NextToken()
+
+	// Then - whitespace and alpha fail, numeric matches
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 attempts, got %d: %v", len(events), events)
+	}
+	if events[0].MatcherName != "tokenizer.WhiteSpaceMatcher" || events[0].Matched() {
+		t.Fatalf("Unexpected first attempt: %v", events[0])
+	}
+	if events[1].MatcherName != "tokenizer.alphaMatcher" || events[1].Consumed != 1 {
+		t.Fatalf("Unexpected second attempt: %v", events[1])
+	}
+	if events[2].Matcher != 2 || !events[2].Matched() || events[2].Token.ValueString() != "12" {
+		t.Fatalf("Unexpected third attempt: %v", events[2])
+	}
+	if events[2].Position != NewPosition(0, 1, 1) {
+		t.Fatalf("Unexpected position: %v", events[2].Position)
+	}
+}
+
+func TestTraceHookMarksPeek(t *testing.T) {
+	tokenizer := NewTokenizerWithoutWhitespace(alphaMatcher)
+	tokenizer.Initialize("abc")
+	var events []TraceEvent
+	tokenizer.SetTraceHook(func(event TraceEvent) {
+		events = append(events, event)
+	})
+
+	tokenizer.PeekToken()
+	tokenizer.SetTraceHook(nil)
+	tokenizer.NextToken()
+
+	if len(events) != 1 || !events[0].Peek {
+		t.Fatalf("Expected a single peek attempt, got %v", events)
+	}
+}
+
+func TestTraceToString(t *testing.T) {
+	// Given
+	tokenizer := NewTokenizer(alphaMatcher, CharMatcherFunc(`Bang`, '!'))
+
+	// When
+	actual := tokenizer.TraceToString("a\n!")
+
+	// Then
+	expected := StripMargin(`
+		|1:1 tokenizer.WhiteSpaceMatcher -> no match, consumed 0
+		|1:1 tokenizer.alphaMatcher -> [Alpha: "a"], consumed 2
+		|1:2 tokenizer.WhiteSpaceMatcher -> [Whitespace: "\n"], consumed 1
+		|2:1 tokenizer.WhiteSpaceMatcher -> no match, consumed 0
+		|2:1 tokenizer.alphaMatcher -> no match, consumed 1
+		|2:1 tokenizer.CharMatcherFunc.func1 -> [Bang: "!"], consumed 1
+		|[EOS]
+	`)
+
+	diff, tdOk := Diff(expected, actual)
+	if !tdOk {
+		t.Fatalf("Trace validation error: \n%v", diff)
+	}
+	if tokenizer.trace != nil {
+		t.Fatalf("Expected TraceToString to restore the previous hook")
+	}
+}
@@ -15,7 +15,8 @@ type SchemaValidator struct {
 	functionRegistry *FunctionRegistry
 	currentPath      []string // Track JSONPath during traversal
 	result           *ValidationResult
-	sourceText       string // Original schema text for source context display
+	sourceText       string                 // Original schema text for source context display
+	duplicateKeys    ast.DuplicateKeyPolicy // Only DuplicateKeyReject reports repeated keys
 }
 
 // NewSchemaValidator creates a new schema validator with built-in types and functions.
@@ -90,6 +91,16 @@ func (v *SchemaValidator) RegisterFunction(name string, desc FunctionDescriptor)
 	return v
 }
 
+// SetDuplicateKeyPolicy sets how objects with repeated keys, as recorded by
+// ObjectNode.Duplicates, are validated. Only ast.DuplicateKeyReject reports
+// each repeat as DUPLICATE_KEY; the default, ast.DuplicateKeyLastWins, and the
+// other policies accept the object as the parser resolved it.
+// Returns the validator for method chaining.
+func (v *SchemaValidator) SetDuplicateKeyPolicy(policy ast.DuplicateKeyPolicy) *SchemaValidator {
+	v.duplicateKeys = policy
+	return v
+}
+
 // VisitLiteral validates a literal node (always valid).
 func (v *SchemaValidator) VisitLiteral(node *ast.LiteralNode) error {
 	// Literals are always valid
@@ -175,13 +186,43 @@ func (v *SchemaValidator) VisitFunction(node *ast.FunctionNode) error {
 
 // VisitObject validates an object node.
 func (v *SchemaValidator) VisitObject(node *ast.ObjectNode) error {
-	for key, prop := range node.Properties() {
+	// Under reject semantics, report each repeated occurrence of a key at its
+	// own position
+	var duplicates []ast.DuplicateKey
+	if v.duplicateKeys == ast.DuplicateKeyReject {
+		duplicates = node.Duplicates()
+	}
+	for _, dup := range duplicates {
+		v.currentPath = append(v.currentPath, dup.Key)
+		for _, pos := range dup.Positions[1:] {
+			v.result.AddError(ValidationError{
+				Position: pos,
+				Path:     v.currentJSONPath(),
+				Code:     ErrCodeDuplicateKey,
+				Message:  fmt.Sprintf("duplicate key %q", dup.Key),
+				Hint:     fmt.Sprintf("First defined at %s; remove or rename the repeated property", dup.Positions[0]),
+			})
+		}
+		v.currentPath = v.currentPath[:len(v.currentPath)-1]
+	}
+
+	for _, entry := range node.PropertyEntries() {
 		// Push property name onto path
-		v.currentPath = append(v.currentPath, key)
+		v.currentPath = append(v.currentPath, entry.Key)
 
 		// Validate the property (errors are collected, not returned)
+		before := len(v.result.Errors)
 		// nolint:errcheck // Error is intentionally ignored as errors are collected in v.result
-		prop.Accept(v)
+		entry.Value.Accept(v)
+
+		// Errors on values without a source position point at the property's key
+		if entry.KeyPosition.IsValid() {
+			for i := before; i < len(v.result.Errors); i++ {
+				if !v.result.Errors[i].Position.IsValid() {
+					v.result.Errors[i].Position = entry.KeyPosition
+				}
+			}
+		}
 
 		// Pop property name from path
 		v.currentPath = v.currentPath[:len(v.currentPath)-1]
@@ -753,3 +753,60 @@ func TestSchemaValidator_FormatMaxArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestSchemaValidator_DuplicateKeys(t *testing.T) {
+	schema, err := ast.NewObjectNodeWithPolicy([]ast.PropertyEntry{
+		{Key: "id", Value: ast.NewTypeNode("UUID", ast.Position{Line: 1, Column: 8}), KeyPosition: ast.Position{Line: 1, Column: 2}},
+		{Key: "name", Value: ast.NewTypeNode("String", ast.Position{Line: 2, Column: 10}), KeyPosition: ast.Position{Line: 2, Column: 2}},
+		{Key: "id", Value: ast.NewTypeNode("Integer", ast.Position{Line: 3, Column: 8}), KeyPosition: ast.Position{Line: 3, Column: 2}},
+		{Key: "id", Value: ast.NewTypeNode("String", ast.Position{Line: 4, Column: 8}), KeyPosition: ast.Position{Line: 4, Column: 2}},
+	}, ast.Position{Line: 1, Column: 1}, ast.DuplicateKeyFirstWins)
+	if err != nil {
+		t.Fatalf("NewObjectNodeWithPolicy() error = %v", err)
+	}
+
+	for _, policy := range []ast.DuplicateKeyPolicy{ast.DuplicateKeyLastWins, ast.DuplicateKeyFirstWins, ast.DuplicateKeyKeepAll} {
+		if result := NewSchemaValidator().SetDuplicateKeyPolicy(policy).ValidateAll(schema); result.HasErrors() {
+			t.Errorf("ValidateAll() under %s = %v, want valid", policy, result.Errors)
+		}
+	}
+
+	result := NewSchemaValidator().SetDuplicateKeyPolicy(ast.DuplicateKeyReject).ValidateAll(schema)
+	if len(result.Errors) != 2 {
+		t.Fatalf("ValidateAll() error count = %d, want 2: %v", len(result.Errors), result.Errors)
+	}
+	for i, line := range []int{3, 4} {
+		err := result.Errors[i]
+		if err.Code != ErrCodeDuplicateKey {
+			t.Errorf("Error %d code = %q, want %q", i, err.Code, ErrCodeDuplicateKey)
+		}
+		if err.Position.Line != line || err.Position.Column != 2 {
+			t.Errorf("Error %d position = %v, want line %d, column 2", i, err.Position, line)
+		}
+		if err.Path != "$.id" {
+			t.Errorf("Error %d path = %q, want $.id", i, err.Path)
+		}
+		if !strings.Contains(err.Hint, "line 1, column 2") {
+			t.Errorf("Error %d hint should point at the first definition, got: %s", i, err.Hint)
+		}
+	}
+}
+
+func TestSchemaValidator_KeyPositionFallback(t *testing.T) {
+	// A value built without a position reports at its property's key
+	schema := ast.NewOrderedObjectNode([]ast.PropertyEntry{
+		{Key: "country", Value: ast.NewTypeNode("CountryCode", ast.ZeroPosition()), KeyPosition: ast.Position{Line: 3, Column: 5}, SeparatorPosition: ast.Position{Line: 3, Column: 14}},
+		{Key: "zip", Value: ast.NewTypeNode("ZipCode", ast.Position{Line: 4, Column: 12}), KeyPosition: ast.Position{Line: 4, Column: 5}},
+	}, ast.Position{Line: 1, Column: 1})
+
+	result := NewSchemaValidator().ValidateAll(schema)
+	if len(result.Errors) != 2 {
+		t.Fatalf("ValidateAll() error count = %d, want 2", len(result.Errors))
+	}
+	if pos := result.Errors[0].Position; pos.Line != 3 || pos.Column != 5 {
+		t.Errorf("Error position = %v, want the key at line 3, column 5", pos)
+	}
+	if pos := result.Errors[1].Position; pos.Line != 4 || pos.Column != 12 {
+		t.Errorf("Error position = %v, want the value at line 4, column 12", pos)
+	}
+}
@@ -18,6 +18,7 @@ const (
 	ErrCodeInvalidArgType    ErrorCode = "INVALID_ARG_TYPE"
 	ErrCodeInvalidArgValue   ErrorCode = "INVALID_ARG_VALUE"
 	ErrCodeCircularReference ErrorCode = "CIRCULAR_REFERENCE"
+	ErrCodeDuplicateKey      ErrorCode = "DUPLICATE_KEY"
 )
 
 // ValidationError represents a semantic validation error with position, path, code, message, and hint.
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/shapestone/shape-core/pkg/ast"
@@ -10,6 +11,7 @@ import (
 type Validator struct {
 	knownTypes     map[string]bool
 	knownFunctions map[string]FunctionRule
+	duplicateKeys  ast.DuplicateKeyPolicy // Only DuplicateKeyReject reports repeated keys
 }
 
 // FunctionRule defines validation rules for a function.
@@ -61,6 +63,20 @@ func (v *Validator) RegisterFunction(name string, rule FunctionRule) *Validator
 	return v
 }
 
+// SetDuplicateKeyPolicy sets how objects with repeated keys, as recorded by
+// ObjectNode.Duplicates, are validated. Only ast.DuplicateKeyReject reports
+// them as errors; the default, ast.DuplicateKeyLastWins, and the other
+// policies accept the object as the parser resolved it.
+// Returns the validator for method chaining.
+//
+// Example:
+//
+//	v := validator.NewValidator().SetDuplicateKeyPolicy(ast.DuplicateKeyReject)
+func (v *Validator) SetDuplicateKeyPolicy(policy ast.DuplicateKeyPolicy) *Validator {
+	v.duplicateKeys = policy
+	return v
+}
+
 // UnregisterType removes a registered type.
 // Note: Cannot unregister built-in types.
 // Returns the validator for method chaining.
@@ -160,11 +176,29 @@ func (v *Validator) VisitFunction(node *ast.FunctionNode) error {
 
 // VisitObject validates an object node.
 func (v *Validator) VisitObject(node *ast.ObjectNode) error {
-	for key, prop := range node.Properties() {
-		if err := prop.Accept(v); err != nil {
+	if dups := node.Duplicates(); len(dups) > 0 && v.duplicateKeys == ast.DuplicateKeyReject {
+		// Fail fast at the first repeat; SchemaValidator reports every one
+		return &ValidationError{
+			Position: dups[0].Positions[1],
+			Code:     ErrCodeDuplicateKey,
+			Message:  fmt.Sprintf("duplicate key %q", dups[0].Key),
+			Hint:     fmt.Sprintf("First defined at %s; remove or rename the repeated property", dups[0].Positions[0]),
+		}
+	}
+	for _, entry := range node.PropertyEntries() {
+		if err := entry.Value.Accept(v); err != nil {
+			// Keep the value's own position; without one, point at the
+			// property's key rather than the object's opening brace
+			var child *ValidationError
+			pos := entry.KeyPosition
+			if errors.As(err, &child) && child.Position.IsValid() {
+				pos = child.Position
+			} else if !pos.IsValid() {
+				pos = node.Position()
+			}
 			return &ValidationError{
-				Position: node.Position(),
-				Message:  fmt.Sprintf("property %q: %v", key, err),
+				Position: pos,
+				Message:  fmt.Sprintf("property %q: %v", entry.Key, err),
 			}
 		}
 	}
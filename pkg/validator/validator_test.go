@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -514,3 +515,56 @@ func TestValidator_ArrayDataNode(t *testing.T) {
 		t.Errorf("Validate(ArrayDataNode) error = %v, want nil", err)
 	}
 }
+
+func TestValidator_DuplicateKey(t *testing.T) {
+	pos := ast.Position{Line: 1, Column: 1}
+	schema := ast.NewOrderedObjectNode([]ast.PropertyEntry{
+		{Key: "id", Value: ast.NewTypeNode("UUID", pos), KeyPosition: ast.Position{Line: 1, Column: 2}},
+		{Key: "id", Value: ast.NewTypeNode("UUID", pos), KeyPosition: ast.Position{Line: 2, Column: 2}},
+	}, pos)
+
+	// Repeats resolved by the parser's policy are accepted by default
+	if err := NewValidator().Validate(schema); err != nil {
+		t.Errorf("Validate() error = %v, want nil without DuplicateKeyReject", err)
+	}
+
+	err := NewValidator().SetDuplicateKeyPolicy(ast.DuplicateKeyReject).Validate(schema)
+	if err == nil {
+		t.Fatal("Validate() expected error for duplicate key")
+	}
+	if !strings.Contains(err.Error(), `duplicate key "id"`) || !strings.Contains(err.Error(), "line 2, column 2") {
+		t.Errorf("Validate() error = %v, want duplicate key at line 2, column 2", err)
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Code != ErrCodeDuplicateKey || !strings.Contains(verr.Hint, "line 1, column 2") {
+		t.Errorf("Validate() error = %#v, want code %s with a hint at the first definition", err, ErrCodeDuplicateKey)
+	}
+}
+
+func TestValidator_PropertyErrorPosition(t *testing.T) {
+	schema := ast.NewOrderedObjectNode([]ast.PropertyEntry{
+		{Key: "id", Value: ast.NewTypeNode("Unknown", ast.Position{Line: 2, Column: 9}), KeyPosition: ast.Position{Line: 2, Column: 3}},
+	}, ast.Position{Line: 1, Column: 1})
+
+	err := NewValidator().Validate(schema)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Validate() error = %v, want *ValidationError", err)
+	}
+	if verr.Position.Line != 2 || verr.Position.Column != 9 {
+		t.Errorf("Error position = %v, want the value at line 2, column 9", verr.Position)
+	}
+
+	// Values without a source position fall back to the key
+	schema = ast.NewOrderedObjectNode([]ast.PropertyEntry{
+		{Key: "id", Value: ast.NewTypeNode("Unknown", ast.Position{}), KeyPosition: ast.Position{Line: 2, Column: 3}},
+	}, ast.Position{Line: 1, Column: 1})
+
+	err = NewValidator().Validate(schema)
+	if !errors.As(err, &verr) {
+		t.Fatalf("Validate() error = %v, want *ValidationError", err)
+	}
+	if verr.Position.Line != 2 || verr.Position.Column != 3 {
+		t.Errorf("Error position = %v, want the key at line 2, column 3", verr.Position)
+	}
+}